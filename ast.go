@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jeremi-traverse/monkey/ast"
+	"github.com/jeremi-traverse/monkey/lexer"
+	"github.com/jeremi-traverse/monkey/parser"
+)
+
+// monkey ast --json|--dot [file]
+// Prints the syntax tree of the file, or of the standard input
+func runAST(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("ast", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	asJSON := flags.Bool("json", false, "print the tree as JSON")
+	asDot := flags.Bool("dot", false, "print the tree as a Graphviz DOT graph")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: monkey ast --json|--dot [file]")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *asJSON == *asDot || flags.NArg() > 1 {
+		flags.Usage()
+		return 2
+	}
+
+	filename, src, err := readSource(flags.Arg(0), stdin)
+	if err != nil {
+		fmt.Fprintf(stderr, "monkey ast: %s\n", err)
+		return 2
+	}
+
+	p := parser.New(lexer.New(string(src)))
+	program := p.ParseFile()
+	if len(p.Errors()) != 0 {
+		fmt.Fprintf(stderr, "%s:\n%s\n", filename, strings.Join(p.Errors(), "\n"))
+		return 2
+	}
+
+	if *asDot {
+		if err := ast.FprintDot(stdout, program); err != nil {
+			fmt.Fprintf(stderr, "monkey ast: %s\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	data, err := ast.EncodeJSON(program)
+	if err != nil {
+		fmt.Fprintf(stderr, "monkey ast: %s\n", err)
+		return 1
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, data, "", "  "); err != nil {
+		fmt.Fprintf(stderr, "monkey ast: %s\n", err)
+		return 1
+	}
+	out.WriteByte('\n')
+	stdout.Write(out.Bytes())
+
+	return 0
+}
+
+// Reads filename, or the standard input when it's empty
+func readSource(filename string, stdin io.Reader) (string, []byte, error) {
+	if filename == "" {
+		src, err := io.ReadAll(stdin)
+		return "<standard input>", src, err
+	}
+
+	src, err := os.ReadFile(filename)
+	return filename, src, err
+}
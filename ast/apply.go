@@ -0,0 +1,228 @@
+package ast
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Called by Apply for each node, the Cursor gives the node's place
+// in the tree and rewrites it
+type ApplyFunc func(*Cursor) bool
+
+// Apply walks the tree under root like Walk and returns it, rewritten
+// by pre and post through their Cursor.
+//
+// pre is called before the children of a node, returning false skips
+// the children and post for that node. post is called after them,
+// returning false stops Apply. Either can be nil.
+//
+// Nodes put in the tree by the cursor aren't walked: a node replaced
+// in pre keeps its new value but its children aren't visited
+func Apply(root Node, pre, post ApplyFunc) Node {
+	a := &application{pre: pre, post: post}
+	a.apply(nil, "", root, func(n Node) { root = n }, nil)
+
+	return root
+}
+
+// The position of a node during Apply
+type Cursor struct {
+	node    Node
+	parent  Node
+	name    string
+	replace func(Node)
+	list    *nodeList // nil when the node isn't in a slice
+}
+
+// Node returns the current node
+func (c *Cursor) Node() Node { return c.node }
+
+// Parent returns the node holding the current node, nil for the root
+func (c *Cursor) Parent() Node { return c.parent }
+
+// Name returns the field of Parent holding the current node,
+// i.e. "Left" or "Statements"
+func (c *Cursor) Name() string { return c.name }
+
+// Index returns the index of the current node in the slice of Parent
+// holding it, -1 when it isn't in a slice
+func (c *Cursor) Index() int {
+	if c.list == nil {
+		return -1
+	}
+
+	return c.list.index
+}
+
+// Replace puts n in place of the current node.
+// n must fit the field, i.e. be an Expression for Left
+func (c *Cursor) Replace(n Node) {
+	c.replace(n)
+	c.node = n
+}
+
+// Delete removes the current node from its slice,
+// it panics when the node isn't in a slice
+func (c *Cursor) Delete() {
+	l := c.mustList("Delete")
+	l.remove(l.index)
+	l.next--
+}
+
+// InsertBefore inserts n before the current node in its slice,
+// it panics when the node isn't in a slice
+func (c *Cursor) InsertBefore(n Node) {
+	l := c.mustList("InsertBefore")
+	l.insert(l.index, n)
+	l.index++
+	l.next++
+}
+
+// InsertAfter inserts n after the current node in its slice,
+// it panics when the node isn't in a slice
+func (c *Cursor) InsertAfter(n Node) {
+	l := c.mustList("InsertAfter")
+	l.insert(l.index+1, n)
+	l.next++
+}
+
+func (c *Cursor) mustList(method string) *nodeList {
+	if c.list == nil {
+		panic(fmt.Sprintf("ast.Cursor.%s: %s.%s isn't a slice", method, typeName(c.parent), c.name))
+	}
+
+	return c.list
+}
+
+func typeName(n Node) string {
+	if n == nil {
+		return "root"
+	}
+
+	return fmt.Sprintf("%T", n)
+}
+
+// A slice of nodes being walked, the cursor moves index and next
+// when it changes the slice
+type nodeList struct {
+	index  int // of the current node
+	next   int // index of the node to visit after it
+	insert func(i int, n Node)
+	remove func(i int)
+}
+
+type application struct {
+	pre, post ApplyFunc
+}
+
+// Applies pre and post to n and its children, set replaces n in its parent.
+// Returns false when post stopped the walk
+func (a *application) apply(parent Node, name string, n Node, set func(Node), list *nodeList) bool {
+	if isNil(n) {
+		return true
+	}
+
+	c := &Cursor{node: n, parent: parent, name: name, replace: set, list: list}
+
+	if a.pre != nil && !a.pre(c) {
+		return true
+	}
+
+	// A replacement is taken as is
+	if c.node == n && !a.children(n) {
+		return false
+	}
+
+	return a.post == nil || a.post(c)
+}
+
+// Same fields and order as Walk
+func (a *application) children(n Node) bool {
+	switch n := n.(type) {
+	case *Program:
+		return applyList(a, n, "Statements", &n.Statements)
+
+	// Statements
+	case *LetStatement:
+		return a.apply(n, "Name", n.Name, func(x Node) { n.Name = x.(Pattern) }, nil) &&
+			a.apply(n, "Value", n.Value, func(x Node) { n.Value = x.(Expression) }, nil)
+	case *ConstStatement:
+		return a.apply(n, "Name", n.Name, func(x Node) { n.Name = x.(Pattern) }, nil) &&
+			a.apply(n, "Value", n.Value, func(x Node) { n.Value = x.(Expression) }, nil)
+	case *ReturnStatement:
+		return a.apply(n, "ReturnValue", n.ReturnValue, func(x Node) { n.ReturnValue = x.(Expression) }, nil)
+	case *ExpressionStatement:
+		return a.apply(n, "Expression", n.Expression, func(x Node) { n.Expression = x.(Expression) }, nil)
+	case *BlockStatement:
+		return applyList(a, n, "Statements", &n.Statements)
+	case *WhileStatement:
+		return a.apply(n, "Condition", n.Condition, func(x Node) { n.Condition = x.(Expression) }, nil) &&
+			a.apply(n, "Body", n.Body, func(x Node) { n.Body = x.(*BlockStatement) }, nil)
+	case *ForStatement:
+		return a.apply(n, "Init", n.Init, func(x Node) { n.Init = x.(Statement) }, nil) &&
+			a.apply(n, "Condition", n.Condition, func(x Node) { n.Condition = x.(Expression) }, nil) &&
+			a.apply(n, "Post", n.Post, func(x Node) { n.Post = x.(Expression) }, nil) &&
+			a.apply(n, "Body", n.Body, func(x Node) { n.Body = x.(*BlockStatement) }, nil)
+	case *ForInStatement:
+		return a.apply(n, "Key", n.Key, func(x Node) { n.Key = x.(*Identifier) }, nil) &&
+			a.apply(n, "Value", n.Value, func(x Node) { n.Value = x.(*Identifier) }, nil) &&
+			a.apply(n, "Iterable", n.Iterable, func(x Node) { n.Iterable = x.(Expression) }, nil) &&
+			a.apply(n, "Body", n.Body, func(x Node) { n.Body = x.(*BlockStatement) }, nil)
+	case *BreakStatement, *ContinueStatement:
+		return true
+
+	// Expressions
+	case *Identifier, *IntegerLiteral, *BigIntegerLiteral, *FloatLiteral, *Boolean:
+		return true
+	case *PrefixExpression:
+		return a.apply(n, "Right", n.Right, func(x Node) { n.Right = x.(Expression) }, nil)
+	case *InfixExpression:
+		return a.apply(n, "Left", n.Left, func(x Node) { n.Left = x.(Expression) }, nil) &&
+			a.apply(n, "Right", n.Right, func(x Node) { n.Right = x.(Expression) }, nil)
+	case *RangeExpression:
+		return a.apply(n, "Start", n.Start, func(x Node) { n.Start = x.(Expression) }, nil) &&
+			a.apply(n, "End", n.End, func(x Node) { n.End = x.(Expression) }, nil)
+	case *IndexExpression:
+		return a.apply(n, "Left", n.Left, func(x Node) { n.Left = x.(Expression) }, nil) &&
+			a.apply(n, "Index", n.Index, func(x Node) { n.Index = x.(Expression) }, nil)
+	case *AssignExpression:
+		return a.apply(n, "Target", n.Target, func(x Node) { n.Target = x.(Expression) }, nil) &&
+			a.apply(n, "Value", n.Value, func(x Node) { n.Value = x.(Expression) }, nil)
+
+	// Patterns
+	case *ArrayPattern:
+		return applyList(a, n, "Elements", &n.Elements) &&
+			a.apply(n, "Rest", n.Rest, func(x Node) { n.Rest = x.(*Identifier) }, nil)
+	case *HashPattern:
+		return applyList(a, n, "Entries", &n.Entries) &&
+			a.apply(n, "Rest", n.Rest, func(x Node) { n.Rest = x.(*Identifier) }, nil)
+	case *HashPatternEntry:
+		return a.apply(n, "Key", n.Key, func(x Node) { n.Key = x.(*Identifier) }, nil) &&
+			a.apply(n, "Value", n.Value, func(x Node) { n.Value = x.(Pattern) }, nil)
+	case *DefaultPattern:
+		return a.apply(n, "Target", n.Target, func(x Node) { n.Target = x.(Pattern) }, nil) &&
+			a.apply(n, "Default", n.Default, func(x Node) { n.Default = x.(Expression) }, nil)
+	}
+
+	panic(fmt.Sprintf("ast.Apply: unexpected node type %T", n))
+}
+
+// Applies to each node of the slice, the cursor can change the slice
+// while it's walked
+func applyList[T Node](a *application, parent Node, name string, nodes *[]T) bool {
+	l := &nodeList{
+		insert: func(i int, n Node) { *nodes = slices.Insert(*nodes, i, n.(T)) },
+		remove: func(i int) { *nodes = slices.Delete(*nodes, i, i+1) },
+	}
+
+	for l.index = 0; l.index < len(*nodes); l.index = l.next {
+		l.next = l.index + 1
+
+		set := func(x Node) { (*nodes)[l.index] = x.(T) }
+		if !a.apply(parent, name, (*nodes)[l.index], set, l) {
+			return false
+		}
+	}
+
+	return true
+}
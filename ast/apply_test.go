@@ -0,0 +1,195 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/jeremi-traverse/monkey/ast"
+	"github.com/jeremi-traverse/monkey/token"
+)
+
+func TestApplyReplace(t *testing.T) {
+	program := parse(t, "let [a = x] = x + x; while (x) { h[x] += 1; }")
+
+	ast.Apply(program, func(c *ast.Cursor) bool {
+		if ident, ok := c.Node().(*ast.Identifier); ok && ident.Value == "x" {
+			c.Replace(identifier("y"))
+		}
+		return true
+	}, nil)
+
+	expected := "let [a = y] = (y + y);while (y) {((h[y]) += 1)}"
+	if program.String() != expected {
+		t.Fatalf("program.String() wrong. expected=%q, got=%q", expected, program.String())
+	}
+}
+
+func TestApplyReplaceHashPatternShorthand(t *testing.T) {
+	program := parse(t, "let {a, b = 1} = x;")
+
+	replaced := 0
+	ast.Apply(program, func(c *ast.Cursor) bool {
+		if ident, ok := c.Node().(*ast.Identifier); ok && ident.Value != "x" {
+			c.Replace(identifier(ident.Value + "2"))
+			replaced++
+		}
+		return true
+	}, nil)
+
+	// The key and the binding of {a} are visited once each
+	if replaced != 4 {
+		t.Fatalf("wrong number of identifiers replaced. expected=4, got=%d", replaced)
+	}
+
+	expected := "let {a2, b2 = 1} = x;"
+	if program.String() != expected {
+		t.Fatalf("program.String() wrong. expected=%q, got=%q", expected, program.String())
+	}
+}
+
+func TestApplyDeleteAndInsert(t *testing.T) {
+	program := parse(t, "a; b; c; while (x) { a; b; }")
+
+	ast.Apply(program, func(c *ast.Cursor) bool {
+		stmt, ok := c.Node().(*ast.ExpressionStatement)
+		if !ok {
+			return true
+		}
+
+		switch stmt.String() {
+		case "a":
+			c.InsertBefore(expressionStatement("before"))
+		case "b":
+			c.Delete()
+		case "c":
+			c.InsertAfter(expressionStatement("after"))
+		}
+		return true
+	}, nil)
+
+	expected := "beforeacafterwhile (x) {beforea}"
+	if program.String() != expected {
+		t.Fatalf("program.String() wrong. expected=%q, got=%q", expected, program.String())
+	}
+}
+
+func TestApplyCursor(t *testing.T) {
+	program := parse(t, "x; -y;")
+
+	type position struct {
+		parent string
+		name   string
+		index  int
+	}
+	positions := map[string]position{}
+
+	ast.Apply(program, func(c *ast.Cursor) bool {
+		if ident, ok := c.Node().(*ast.Identifier); ok {
+			positions[ident.Value] = position{c.Parent().String(), c.Name(), c.Index()}
+		}
+		if stmt, ok := c.Node().(*ast.ExpressionStatement); ok {
+			positions[stmt.String()] = position{"", c.Name(), c.Index()}
+		}
+		return true
+	}, nil)
+
+	expected := map[string]position{
+		"x":    {"x", "Expression", -1},
+		"y":    {"(-y)", "Right", -1},
+		"(-y)": {"", "Statements", 1},
+	}
+
+	for key, pos := range expected {
+		if positions[key] != pos {
+			t.Errorf("wrong cursor for %s. expected=%+v, got=%+v", key, pos, positions[key])
+		}
+	}
+}
+
+func TestApplyPostOrderAndAbort(t *testing.T) {
+	program := parse(t, "1 + 2; 3;")
+
+	visited := []string{}
+	ast.Apply(program, nil, func(c *ast.Cursor) bool {
+		visited = append(visited, c.Node().String())
+		// Stop at the first statement
+		_, isStatement := c.Node().(*ast.ExpressionStatement)
+		return !isStatement
+	})
+
+	expected := []string{"1", "2", "(1 + 2)", "(1 + 2)"}
+	if len(visited) != len(expected) {
+		t.Fatalf("wrong visits. expected=%q, got=%q", expected, visited)
+	}
+	for i := range expected {
+		if visited[i] != expected[i] {
+			t.Fatalf("wrong visits. expected=%q, got=%q", expected, visited)
+		}
+	}
+}
+
+func TestApplyReplaceRoot(t *testing.T) {
+	exp := parse(t, "x").Statements[0].(*ast.ExpressionStatement).Expression
+
+	result := ast.Apply(exp, func(c *ast.Cursor) bool {
+		c.Replace(identifier("y"))
+		return false
+	}, nil)
+
+	if result.String() != "y" {
+		t.Fatalf("root not replaced. got=%q", result.String())
+	}
+}
+
+func TestApplyReplacementNotWalked(t *testing.T) {
+	program := parse(t, "-x; y;")
+
+	visited := []string{}
+	ast.Apply(program, func(c *ast.Cursor) bool {
+		visited = append(visited, c.Node().String())
+
+		if _, ok := c.Node().(*ast.PrefixExpression); ok {
+			c.Replace(&ast.PrefixExpression{Operator: "!", Right: identifier("z")})
+		}
+		return true
+	}, func(c *ast.Cursor) bool {
+		visited = append(visited, "post "+c.Node().String())
+		return true
+	})
+
+	expected := []string{
+		"(-x)y", "(-x)", "(-x)", "post (!z)", "post (!z)",
+		"y", "y", "post y", "post y", "post (!z)y",
+	}
+	if len(visited) != len(expected) {
+		t.Fatalf("wrong visits. expected=%q, got=%q", expected, visited)
+	}
+	for i := range expected {
+		if visited[i] != expected[i] {
+			t.Fatalf("wrong visits. expected=%q, got=%q", expected, visited)
+		}
+	}
+}
+
+func TestApplyDeleteOutsideSlice(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("Delete didn't panic outside of a slice")
+		}
+	}()
+
+	ast.Apply(parse(t, "-x"), func(c *ast.Cursor) bool {
+		if _, ok := c.Node().(*ast.Identifier); ok {
+			c.Delete()
+		}
+		return true
+	}, nil)
+}
+
+func identifier(name string) *ast.Identifier {
+	return &ast.Identifier{Token: token.Token{Type: token.IDENT, Literal: name}, Value: name}
+}
+
+func expressionStatement(name string) *ast.ExpressionStatement {
+	ident := identifier(name)
+	return &ast.ExpressionStatement{Token: ident.Token, Expression: ident}
+}
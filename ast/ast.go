@@ -2,6 +2,8 @@ package ast
 
 import (
 	"bytes"
+	"math/big"
+	"strings"
 
 	"github.com/jeremi-traverse/monkey/token"
 )
@@ -23,14 +25,38 @@ type Expression interface {
 	expressionNode()
 }
 
+// A Pattern is the left part of a binding: x, [a, b] or {name, age: years}
+type Pattern interface {
+	Node
+	patternNode()
+}
+
 type Program struct {
 	Statements []Statement
+	Comments   []*Comment // Every comment of the source, in order
 }
 
+// A line comment: // ...
+// Comments aren't statements, they're only kept to be printed back
+type Comment struct {
+	Token token.Token
+}
+
+func (c *Comment) TokenLiteral() string { return c.Token.Literal }
+func (c *Comment) String() string       { return c.Token.Literal }
+
 // Implements Statement interface
 type LetStatement struct {
 	Token token.Token
-	Name  *Identifier // Identifier of the binding (left part)
+	Name  Pattern // Binding (left part)
+	Value Expression
+}
+
+// Implements Statement interface
+// Like a LetStatement but the binding can't be reassigned
+type ConstStatement struct {
+	Token token.Token
+	Name  Pattern
 	Value Expression
 }
 
@@ -50,6 +76,17 @@ type IntegerLiteral struct {
 	Value int64
 }
 
+// Integer literal that doesn't fit in an int64
+type BigIntegerLiteral struct {
+	Token token.Token
+	Value *big.Int
+}
+
+type FloatLiteral struct {
+	Token token.Token
+	Value float64
+}
+
 type PrefixExpression struct {
 	Token    token.Token
 	Operator string
@@ -96,10 +133,27 @@ func (ls *LetStatement) String() string {
 	var out bytes.Buffer
 
 	out.WriteString(ls.TokenLiteral() + " ")
-	out.WriteString(ls.Name.String() + " ")
+	out.WriteString(ls.Name.String() + " = ")
 
 	if ls.Value != nil {
-		out.WriteString(ls.Value.String() + " ")
+		out.WriteString(ls.Value.String())
+	}
+
+	out.WriteString(";")
+
+	return out.String()
+}
+
+func (cs *ConstStatement) statementNode()       {}
+func (cs *ConstStatement) TokenLiteral() string { return cs.Token.Literal }
+func (cs *ConstStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(cs.TokenLiteral() + " ")
+	out.WriteString(cs.Name.String() + " = ")
+
+	if cs.Value != nil {
+		out.WriteString(cs.Value.String())
 	}
 
 	out.WriteString(";")
@@ -120,6 +174,14 @@ func (il *IntegerLiteral) expressionNode()      {}
 func (il *IntegerLiteral) TokenLiteral() string { return il.Token.Literal }
 func (il *IntegerLiteral) String() string       { return il.Token.Literal }
 
+func (bl *BigIntegerLiteral) expressionNode()      {}
+func (bl *BigIntegerLiteral) TokenLiteral() string { return bl.Token.Literal }
+func (bl *BigIntegerLiteral) String() string       { return bl.Token.Literal }
+
+func (fl *FloatLiteral) expressionNode()      {}
+func (fl *FloatLiteral) TokenLiteral() string { return fl.Token.Literal }
+func (fl *FloatLiteral) String() string       { return fl.Token.Literal }
+
 func (rs *ReturnStatement) statementNode()       {}
 func (rs *ReturnStatement) TokenLiteral() string { return rs.Token.Literal }
 func (rs *ReturnStatement) String() string {
@@ -144,6 +206,7 @@ type Identifier struct {
 }
 
 func (i *Identifier) expressionNode()      {}
+func (i *Identifier) patternNode()         {}
 func (i *Identifier) TokenLiteral() string { return i.Token.Literal }
 func (i *Identifier) String() string       { return i.Value }
 
@@ -173,3 +236,264 @@ func (p *Program) String() string {
 
 	return out.String()
 }
+
+// { x; y; }
+type BlockStatement struct {
+	Token      token.Token // The { token
+	Statements []Statement
+	Rbrace     token.Token // The } token
+}
+
+func (bs *BlockStatement) statementNode()       {}
+func (bs *BlockStatement) TokenLiteral() string { return bs.Token.Literal }
+func (bs *BlockStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("{")
+	for _, s := range bs.Statements {
+		out.WriteString(s.String())
+	}
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// while (x < 10) { ... }
+type WhileStatement struct {
+	Token     token.Token
+	Condition Expression
+	Body      *BlockStatement
+}
+
+func (ws *WhileStatement) statementNode()       {}
+func (ws *WhileStatement) TokenLiteral() string { return ws.Token.Literal }
+func (ws *WhileStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("while (")
+	out.WriteString(ws.Condition.String())
+	out.WriteString(") ")
+	out.WriteString(ws.Body.String())
+
+	return out.String()
+}
+
+// for (let i = 0; i < 10; i = i + 1) { ... }
+// Init, Condition and Post are nil when left empty
+type ForStatement struct {
+	Token     token.Token
+	Init      Statement
+	Condition Expression
+	Post      Expression
+	Body      *BlockStatement
+}
+
+func (fs *ForStatement) statementNode()       {}
+func (fs *ForStatement) TokenLiteral() string { return fs.Token.Literal }
+func (fs *ForStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("for (")
+	if fs.Init != nil {
+		out.WriteString(strings.TrimSuffix(fs.Init.String(), ";"))
+	}
+	out.WriteString("; ")
+	if fs.Condition != nil {
+		out.WriteString(fs.Condition.String())
+	}
+	out.WriteString("; ")
+	if fs.Post != nil {
+		out.WriteString(fs.Post.String())
+	}
+	out.WriteString(") ")
+	out.WriteString(fs.Body.String())
+
+	return out.String()
+}
+
+type BreakStatement struct {
+	Token token.Token
+}
+
+func (bs *BreakStatement) statementNode()       {}
+func (bs *BreakStatement) TokenLiteral() string { return bs.Token.Literal }
+func (bs *BreakStatement) String() string       { return bs.Token.Literal + ";" }
+
+type ContinueStatement struct {
+	Token token.Token
+}
+
+func (cs *ContinueStatement) statementNode()       {}
+func (cs *ContinueStatement) TokenLiteral() string { return cs.Token.Literal }
+func (cs *ContinueStatement) String() string       { return cs.Token.Literal + ";" }
+
+// for (x in arr) { ... } or for (k, v in hash) { ... }
+// Key is nil when only the value is bound
+type ForInStatement struct {
+	Token    token.Token
+	Key      *Identifier
+	Value    *Identifier
+	Iterable Expression
+	Body     *BlockStatement
+}
+
+func (fs *ForInStatement) statementNode()       {}
+func (fs *ForInStatement) TokenLiteral() string { return fs.Token.Literal }
+func (fs *ForInStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("for (")
+	if fs.Key != nil {
+		out.WriteString(fs.Key.String() + ", ")
+	}
+	out.WriteString(fs.Value.String())
+	out.WriteString(" in ")
+	out.WriteString(fs.Iterable.String())
+	out.WriteString(") ")
+	out.WriteString(fs.Body.String())
+
+	return out.String()
+}
+
+// 0..10 excludes the end, 0..=10 includes it
+type RangeExpression struct {
+	Token     token.Token // The .. or ..= token
+	Start     Expression
+	End       Expression
+	Inclusive bool
+}
+
+func (re *RangeExpression) expressionNode()      {}
+func (re *RangeExpression) TokenLiteral() string { return re.Token.Literal }
+func (re *RangeExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(re.Start.String())
+	out.WriteString(re.Token.Literal)
+	out.WriteString(re.End.String())
+	out.WriteString(")")
+
+	return out.String()
+}
+
+// arr[i]
+type IndexExpression struct {
+	Token token.Token // The [ token
+	Left  Expression
+	Index Expression
+}
+
+func (ie *IndexExpression) expressionNode()      {}
+func (ie *IndexExpression) TokenLiteral() string { return ie.Token.Literal }
+func (ie *IndexExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(ie.Left.String())
+	out.WriteString("[")
+	out.WriteString(ie.Index.String())
+	out.WriteString("])")
+
+	return out.String()
+}
+
+// x = 5, x += 1, arr[i] = v
+// Target is either an *Identifier or an *IndexExpression
+type AssignExpression struct {
+	Token    token.Token // The = token or the compound operator
+	Target   Expression
+	Operator string
+	Value    Expression
+}
+
+func (ae *AssignExpression) expressionNode()      {}
+func (ae *AssignExpression) TokenLiteral() string { return ae.Token.Literal }
+func (ae *AssignExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(ae.Target.String())
+	out.WriteString(" " + ae.Operator + " ")
+	out.WriteString(ae.Value.String())
+	out.WriteString(")")
+
+	return out.String()
+}
+
+// let [a, b = 2, ...rest] = arr;
+// Rest is nil when the remaining elements aren't bound
+type ArrayPattern struct {
+	Token    token.Token // The [ token
+	Elements []Pattern
+	Rest     *Identifier
+}
+
+func (ap *ArrayPattern) patternNode()         {}
+func (ap *ArrayPattern) TokenLiteral() string { return ap.Token.Literal }
+func (ap *ArrayPattern) String() string {
+	elements := []string{}
+	for _, el := range ap.Elements {
+		elements = append(elements, el.String())
+	}
+	if ap.Rest != nil {
+		elements = append(elements, "..."+ap.Rest.String())
+	}
+
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+// let {name, age: years, ...rest} = person;
+type HashPattern struct {
+	Token   token.Token // The { token
+	Entries []*HashPatternEntry
+	Rest    *Identifier
+}
+
+// {name} is the shorthand of {name: name}
+type HashPatternEntry struct {
+	Key   *Identifier
+	Value Pattern
+}
+
+func (hp *HashPattern) patternNode()         {}
+func (hp *HashPattern) TokenLiteral() string { return hp.Token.Literal }
+func (hp *HashPattern) String() string {
+	entries := []string{}
+	for _, entry := range hp.Entries {
+		entries = append(entries, entry.String())
+	}
+	if hp.Rest != nil {
+		entries = append(entries, "..."+hp.Rest.String())
+	}
+
+	return "{" + strings.Join(entries, ", ") + "}"
+}
+
+func (he *HashPatternEntry) TokenLiteral() string { return he.Key.TokenLiteral() }
+func (he *HashPatternEntry) String() string {
+	target := he.Value
+	if dp, ok := target.(*DefaultPattern); ok {
+		target = dp.Target
+	}
+
+	// Shorthand {name} or {name = 1}
+	if ident, ok := target.(*Identifier); ok && ident.Value == he.Key.Value {
+		return he.Value.String()
+	}
+
+	return he.Key.String() + ": " + he.Value.String()
+}
+
+// a = 1 inside a pattern, Default is used when the value is missing
+type DefaultPattern struct {
+	Token   token.Token // The = token
+	Target  Pattern
+	Default Expression
+}
+
+func (dp *DefaultPattern) patternNode()         {}
+func (dp *DefaultPattern) TokenLiteral() string { return dp.Token.Literal }
+func (dp *DefaultPattern) String() string {
+	return dp.Target.String() + " = " + dp.Default.String()
+}
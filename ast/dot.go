@@ -0,0 +1,70 @@
+package ast
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// FprintDot writes node as a Graphviz DOT graph to w.
+// Each node is a box labelled with its type and, when it has one,
+// its operator or literal. Edges are labelled with the field holding
+// the child, i.e. Left, Right or Statements[2]
+func FprintDot(w io.Writer, node Node) error {
+	out := bufio.NewWriter(w)
+	ids := map[Node]int{}
+
+	fmt.Fprintln(out, "digraph AST {")
+	fmt.Fprintln(out, "\tnode [shape=box, fontname=monospace];")
+
+	Apply(node, func(c *Cursor) bool {
+		id := len(ids)
+		ids[c.Node()] = id
+		fmt.Fprintf(out, "\tn%d [label=%s];\n", id, dotString(dotLabel(c.Node())))
+
+		// Parent is nil for the root, it has no incoming edge
+		if parent, ok := ids[c.Parent()]; ok {
+			edge := c.Name()
+			if c.Index() >= 0 {
+				edge = fmt.Sprintf("%s[%d]", edge, c.Index())
+			}
+			fmt.Fprintf(out, "\tn%d -> n%d [label=%s];\n", parent, id, dotString(edge))
+		}
+		return true
+	}, nil)
+
+	fmt.Fprintln(out, "}")
+	return out.Flush()
+}
+
+func dotLabel(node Node) string {
+	label := reflect.TypeOf(node).Elem().Name()
+
+	switch n := node.(type) {
+	case *Identifier:
+		return label + "\n" + n.Value
+	case *IntegerLiteral, *BigIntegerLiteral, *FloatLiteral, *Boolean:
+		return label + "\n" + n.TokenLiteral()
+	case *PrefixExpression:
+		return label + "\n" + n.Operator
+	case *InfixExpression:
+		return label + "\n" + n.Operator
+	case *AssignExpression:
+		return label + "\n" + n.Operator
+	case *RangeExpression:
+		return label + "\n" + n.Token.Literal
+	case *HashPatternEntry:
+		return label + "\n" + n.Key.Value
+	}
+
+	return label
+}
+
+// Quotes s as a DOT string, lines are centered
+func dotString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + strings.ReplaceAll(s, "\n", `\n`) + `"`
+}
@@ -0,0 +1,58 @@
+package ast_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jeremi-traverse/monkey/ast"
+)
+
+func TestFprintDot(t *testing.T) {
+	program := parse(t, "a + b * c")
+
+	var out bytes.Buffer
+	if err := ast.FprintDot(&out, program.Statements[0]); err != nil {
+		t.Fatalf("FprintDot failed: %s", err)
+	}
+
+	expected := `digraph AST {
+	node [shape=box, fontname=monospace];
+	n0 [label="ExpressionStatement"];
+	n1 [label="InfixExpression\n+"];
+	n0 -> n1 [label="Expression"];
+	n2 [label="Identifier\na"];
+	n1 -> n2 [label="Left"];
+	n3 [label="InfixExpression\n*"];
+	n1 -> n3 [label="Right"];
+	n4 [label="Identifier\nb"];
+	n3 -> n4 [label="Left"];
+	n5 [label="Identifier\nc"];
+	n3 -> n5 [label="Right"];
+}
+`
+
+	if out.String() != expected {
+		t.Fatalf("wrong graph.\nexpected=%s\ngot=%s", expected, out.String())
+	}
+}
+
+func TestFprintDotProgram(t *testing.T) {
+	program := parse(t, "let x = 1; y += 2;")
+
+	var out bytes.Buffer
+	if err := ast.FprintDot(&out, program); err != nil {
+		t.Fatalf("FprintDot failed: %s", err)
+	}
+
+	for _, line := range []string{
+		`n0 [label="Program"];`,
+		`n0 -> n1 [label="Statements[0]"];`,
+		`n4 [label="ExpressionStatement"];`,
+		`n0 -> n4 [label="Statements[1]"];`,
+		`n5 [label="AssignExpression\n+="];`,
+	} {
+		if !bytes.Contains(out.Bytes(), []byte(line)) {
+			t.Errorf("graph doesn't contain %q.\ngot=%s", line, out.String())
+		}
+	}
+}
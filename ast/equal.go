@@ -0,0 +1,163 @@
+package ast
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/jeremi-traverse/monkey/token"
+)
+
+type EqualOptions struct {
+	// Only compare the type and literal of tokens, not their line, column
+	// and leading trivia
+	IgnorePositions bool
+}
+
+// Equal reports whether a and b are the same tree: same node types,
+// same fields and same children. When they differ, path locates the
+// first difference from the roots, i.e.
+//
+//	Statements[0].Value.Right.Value: 5 != 6
+//
+// A nil and an empty slice are equal
+func Equal(a, b Node, opts EqualOptions) (equal bool, path string) {
+	path = opts.diff("", reflect.ValueOf(&a).Elem(), reflect.ValueOf(&b).Elem())
+	return path == "", path
+}
+
+var (
+	tokenType  = reflect.TypeOf(token.Token{})
+	bigIntType = reflect.TypeOf((*big.Int)(nil))
+)
+
+// Returns the path to the first difference, "" when a and b are equal
+func (opts EqualOptions) diff(path string, a, b reflect.Value) string {
+	mismatch := func(format string, args ...any) string {
+		if path == "" {
+			path = "root"
+		}
+		return path + ": " + fmt.Sprintf(format, args...)
+	}
+
+	switch a.Kind() {
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				return mismatch("%s != %s", describe(a), describe(b))
+			}
+			return ""
+		}
+		if a.Elem().Type() != b.Elem().Type() {
+			return mismatch("%s != %s", a.Elem().Type(), b.Elem().Type())
+		}
+		return opts.diff(path, a.Elem(), b.Elem())
+
+	case reflect.Pointer:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				return mismatch("%s != %s", describe(a), describe(b))
+			}
+			return ""
+		}
+		if a.Type() == bigIntType {
+			if a.Interface().(*big.Int).Cmp(b.Interface().(*big.Int)) != 0 {
+				return mismatch("%s != %s", a.Interface(), b.Interface())
+			}
+			return ""
+		}
+		return opts.diff(path, a.Elem(), b.Elem())
+
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			name := a.Type().Field(i).Name
+			if a.Type() == tokenType && opts.IgnorePositions && (name == "Line" || name == "Column" || name == "Leading") {
+				continue
+			}
+
+			fieldPath := name
+			if path != "" {
+				fieldPath = path + "." + name
+			}
+			if d := opts.diff(fieldPath, a.Field(i), b.Field(i)); d != "" {
+				return d
+			}
+		}
+		return ""
+
+	case reflect.Slice:
+		if a.Len() != b.Len() {
+			return mismatch("len %d != %d", a.Len(), b.Len())
+		}
+		for i := 0; i < a.Len(); i++ {
+			if d := opts.diff(fmt.Sprintf("%s[%d]", path, i), a.Index(i), b.Index(i)); d != "" {
+				return d
+			}
+		}
+		return ""
+	}
+
+	if a.Interface() != b.Interface() {
+		return mismatch("%#v != %#v", a.Interface(), b.Interface())
+	}
+	return ""
+}
+
+func describe(v reflect.Value) string {
+	if v.IsNil() {
+		return "nil"
+	}
+	if v.Kind() == reflect.Interface {
+		return v.Elem().Type().String()
+	}
+
+	return v.Type().String()
+}
+
+// Clone returns a deep copy of node: the copy shares no node, slice
+// or big integer with the original, so either can be modified freely
+func Clone[T Node](node T) T {
+	return cloneValue(reflect.ValueOf(&node).Elem()).Interface().(T)
+}
+
+func cloneValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(cloneValue(v.Elem()))
+		return c
+
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		if v.Type() == bigIntType {
+			return reflect.ValueOf(new(big.Int).Set(v.Interface().(*big.Int)))
+		}
+		c := reflect.New(v.Type().Elem())
+		c.Elem().Set(cloneValue(v.Elem()))
+		return c
+
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			c.Field(i).Set(cloneValue(v.Field(i)))
+		}
+		return c
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(cloneValue(v.Index(i)))
+		}
+		return c
+	}
+
+	return v
+}
@@ -0,0 +1,112 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/jeremi-traverse/monkey/ast"
+	"github.com/jeremi-traverse/monkey/lexer"
+	"github.com/jeremi-traverse/monkey/parser"
+)
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		a, b            string
+		ignorePositions bool
+		expected        string
+	}{
+		{"let x = 5;", "let x = 5;", false, ""},
+		{"let x = 5;", "let  x = 5;", true, ""},
+		{"let x = 5;", "let  x = 5;", false, "Statements[0].Name.Token.Column: 5 != 6"},
+		{"let x = 1 + 5;", "let x = 1 + 6;", true, `Statements[0].Value.Right.Token.Literal: "5" != "6"`},
+		{"x = 1;", "x += 1;", true, `Statements[0].Expression.Token.Type: "=" != "+="`},
+		{"let x = -1;", "let x = 1;", true, "Statements[0].Value: *ast.PrefixExpression != *ast.IntegerLiteral"},
+		{"a; b;", "a;", true, "Statements: len 2 != 1"},
+		{"return;", "return 1;", true, "Statements[0].ReturnValue: nil != *ast.IntegerLiteral"},
+		{"let [a, ...b] = c;", "let [a] = c;", true, "Statements[0].Name.Rest: *ast.Identifier != nil"},
+		{"9223372036854775808;", "9223372036854775809;", true,
+			`Statements[0].Token.Literal: "9223372036854775808" != "9223372036854775809"`},
+	}
+
+	for _, tt := range tests {
+		equal, path := ast.Equal(parse(t, tt.a), parse(t, tt.b), ast.EqualOptions{IgnorePositions: tt.ignorePositions})
+
+		if equal != (tt.expected == "") || path != tt.expected {
+			t.Errorf("Equal(%q, %q) wrong. expected=%q, got=%v %q", tt.a, tt.b, tt.expected, equal, path)
+		}
+	}
+}
+
+// The trivia kept by lexer.NewWithTrivia is a position detail
+func TestEqualTrivia(t *testing.T) {
+	input := "let x = 5; // five"
+	p := parser.New(lexer.NewWithTrivia(input))
+	trivia := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser errors: %v", p.Errors())
+	}
+
+	equal, path := ast.Equal(trivia, parse(t, input), ast.EqualOptions{IgnorePositions: true})
+	if !equal {
+		t.Errorf("Equal with IgnorePositions wrong. got=%q", path)
+	}
+
+	equal, path = ast.Equal(trivia, parse(t, input), ast.EqualOptions{})
+	if equal || path != `Statements[0].Name.Token.Leading: " " != ""` {
+		t.Errorf("Equal wrong. got=%v %q", equal, path)
+	}
+}
+
+func TestEqualNodes(t *testing.T) {
+	equal, path := ast.Equal(identifier("x"), nil, ast.EqualOptions{})
+	if equal || path != "root: *ast.Identifier != nil" {
+		t.Errorf("Equal(x, nil) wrong. got=%v %q", equal, path)
+	}
+
+	equal, _ = ast.Equal(nil, nil, ast.EqualOptions{})
+	if !equal {
+		t.Errorf("Equal(nil, nil) wrong. got=%v", equal)
+	}
+}
+
+func TestClone(t *testing.T) {
+	program := parse(t, "let {a, b: [c = 1]} = x; for (k, v in 0..n) { h[k] += 9223372036854775808; }")
+
+	clone := ast.Clone(program)
+	if equal, path := ast.Equal(program, clone, ast.EqualOptions{}); !equal {
+		t.Fatalf("clone differs from the original at %s", path)
+	}
+
+	// Modifying the clone leaves the original alone
+	ast.Inspect(clone, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.Identifier:
+			n.Value = "changed"
+		case *ast.BigIntegerLiteral:
+			n.Value.SetInt64(0)
+		case *ast.BlockStatement:
+			n.Statements[0] = nil
+		}
+		return true
+	})
+
+	expected := "let {a, b: [c = 1]} = x;for (k, v in (0..n)) {((h[k]) += 9223372036854775808)}"
+	if program.String() != expected {
+		t.Fatalf("original modified. expected=%q, got=%q", expected, program.String())
+	}
+
+	if program.Statements[1].(*ast.ForInStatement).Body.Statements[0] == nil {
+		t.Fatalf("original block modified")
+	}
+}
+
+func TestCloneInterface(t *testing.T) {
+	var node ast.Node = identifier("x")
+
+	clone := ast.Clone(node)
+	if clone == node {
+		t.Fatalf("Clone returned the same node")
+	}
+	if equal, path := ast.Equal(node, clone, ast.EqualOptions{}); !equal {
+		t.Fatalf("clone differs from the original at %s", path)
+	}
+}
@@ -0,0 +1,293 @@
+package ast
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Every concrete node type, by the name used as "type" in JSON
+var nodeTypes = map[string]reflect.Type{}
+
+func init() {
+	for _, node := range []Node{
+		&Program{}, &Comment{},
+		&LetStatement{}, &ConstStatement{}, &ReturnStatement{}, &ExpressionStatement{},
+		&BlockStatement{}, &WhileStatement{}, &ForStatement{}, &ForInStatement{},
+		&BreakStatement{}, &ContinueStatement{},
+		&Identifier{}, &IntegerLiteral{}, &BigIntegerLiteral{}, &FloatLiteral{}, &Boolean{},
+		&PrefixExpression{}, &InfixExpression{}, &RangeExpression{},
+		&IndexExpression{}, &AssignExpression{},
+		&ArrayPattern{}, &HashPattern{}, &HashPatternEntry{}, &DefaultPattern{},
+	} {
+		t := reflect.TypeOf(node).Elem()
+		nodeTypes[t.Name()] = t
+	}
+}
+
+var nodeType = reflect.TypeOf((*Node)(nil)).Elem()
+
+// EncodeJSON encodes node and its children as JSON objects.
+// Each object has a "type" field naming the node type (i.e. "LetStatement")
+// followed by the node's fields in camel case, tokens carry their position:
+//
+//	{"type": "Identifier", "token": {"type": "IDENT", "literal": "x", "line": 1, "column": 5}, "value": "x"}
+//
+// Missing children are encoded as null
+func EncodeJSON(node Node) ([]byte, error) {
+	var out bytes.Buffer
+	if err := marshalNode(&out, reflect.ValueOf(node)); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+func marshalNode(out *bytes.Buffer, v reflect.Value) error {
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if !v.IsValid() || (v.Kind() == reflect.Pointer && v.IsNil()) {
+		out.WriteString("null")
+		return nil
+	}
+
+	if v.Kind() != reflect.Pointer || nodeTypes[v.Type().Elem().Name()] != v.Type().Elem() {
+		return fmt.Errorf("ast.EncodeJSON: unexpected node type %s", v.Type())
+	}
+
+	t := v.Type().Elem()
+	fmt.Fprintf(out, `{"type":%q`, t.Name())
+
+	v = v.Elem()
+	for i := 0; i < t.NumField(); i++ {
+		fmt.Fprintf(out, ",%q:", jsonName(t.Field(i).Name))
+		if err := marshalValue(out, v.Field(i)); err != nil {
+			return err
+		}
+	}
+
+	out.WriteByte('}')
+	return nil
+}
+
+func marshalValue(out *bytes.Buffer, v reflect.Value) error {
+	switch {
+	case isNodeType(v.Type()):
+		return marshalNode(out, v)
+	case v.Kind() == reflect.Slice && isNodeType(v.Type().Elem()):
+		out.WriteByte('[')
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				out.WriteByte(',')
+			}
+			if err := marshalNode(out, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		out.WriteByte(']')
+		return nil
+	}
+
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return err
+	}
+
+	out.Write(data)
+	return nil
+}
+
+// DecodeJSON decodes a node encoded by EncodeJSON
+func DecodeJSON(data []byte) (Node, error) {
+	v, err := unmarshalNode(data)
+	if err != nil {
+		return nil, err
+	}
+	if !v.IsValid() {
+		return nil, nil
+	}
+
+	return v.Interface().(Node), nil
+}
+
+// The nodes implement json.Marshaler and json.Unmarshaler with
+// EncodeJSON and DecodeJSON, so encoding/json reads and writes them
+// with their "type". Decoding into a node fails when the JSON holds
+// another node type
+
+func (p *Program) MarshalJSON() ([]byte, error)    { return EncodeJSON(p) }
+func (p *Program) UnmarshalJSON(data []byte) error { return decodeInto(data, p) }
+
+func (c *Comment) MarshalJSON() ([]byte, error)    { return EncodeJSON(c) }
+func (c *Comment) UnmarshalJSON(data []byte) error { return decodeInto(data, c) }
+
+func (ls *LetStatement) MarshalJSON() ([]byte, error)    { return EncodeJSON(ls) }
+func (ls *LetStatement) UnmarshalJSON(data []byte) error { return decodeInto(data, ls) }
+
+func (cs *ConstStatement) MarshalJSON() ([]byte, error)    { return EncodeJSON(cs) }
+func (cs *ConstStatement) UnmarshalJSON(data []byte) error { return decodeInto(data, cs) }
+
+func (rs *ReturnStatement) MarshalJSON() ([]byte, error)    { return EncodeJSON(rs) }
+func (rs *ReturnStatement) UnmarshalJSON(data []byte) error { return decodeInto(data, rs) }
+
+func (es *ExpressionStatement) MarshalJSON() ([]byte, error)    { return EncodeJSON(es) }
+func (es *ExpressionStatement) UnmarshalJSON(data []byte) error { return decodeInto(data, es) }
+
+func (bs *BlockStatement) MarshalJSON() ([]byte, error)    { return EncodeJSON(bs) }
+func (bs *BlockStatement) UnmarshalJSON(data []byte) error { return decodeInto(data, bs) }
+
+func (ws *WhileStatement) MarshalJSON() ([]byte, error)    { return EncodeJSON(ws) }
+func (ws *WhileStatement) UnmarshalJSON(data []byte) error { return decodeInto(data, ws) }
+
+func (fs *ForStatement) MarshalJSON() ([]byte, error)    { return EncodeJSON(fs) }
+func (fs *ForStatement) UnmarshalJSON(data []byte) error { return decodeInto(data, fs) }
+
+func (fs *ForInStatement) MarshalJSON() ([]byte, error)    { return EncodeJSON(fs) }
+func (fs *ForInStatement) UnmarshalJSON(data []byte) error { return decodeInto(data, fs) }
+
+func (bs *BreakStatement) MarshalJSON() ([]byte, error)    { return EncodeJSON(bs) }
+func (bs *BreakStatement) UnmarshalJSON(data []byte) error { return decodeInto(data, bs) }
+
+func (cs *ContinueStatement) MarshalJSON() ([]byte, error)    { return EncodeJSON(cs) }
+func (cs *ContinueStatement) UnmarshalJSON(data []byte) error { return decodeInto(data, cs) }
+
+func (i *Identifier) MarshalJSON() ([]byte, error)    { return EncodeJSON(i) }
+func (i *Identifier) UnmarshalJSON(data []byte) error { return decodeInto(data, i) }
+
+func (il *IntegerLiteral) MarshalJSON() ([]byte, error)    { return EncodeJSON(il) }
+func (il *IntegerLiteral) UnmarshalJSON(data []byte) error { return decodeInto(data, il) }
+
+func (bl *BigIntegerLiteral) MarshalJSON() ([]byte, error)    { return EncodeJSON(bl) }
+func (bl *BigIntegerLiteral) UnmarshalJSON(data []byte) error { return decodeInto(data, bl) }
+
+func (fl *FloatLiteral) MarshalJSON() ([]byte, error)    { return EncodeJSON(fl) }
+func (fl *FloatLiteral) UnmarshalJSON(data []byte) error { return decodeInto(data, fl) }
+
+func (i *Boolean) MarshalJSON() ([]byte, error)    { return EncodeJSON(i) }
+func (i *Boolean) UnmarshalJSON(data []byte) error { return decodeInto(data, i) }
+
+func (pe *PrefixExpression) MarshalJSON() ([]byte, error)    { return EncodeJSON(pe) }
+func (pe *PrefixExpression) UnmarshalJSON(data []byte) error { return decodeInto(data, pe) }
+
+func (ie *InfixExpression) MarshalJSON() ([]byte, error)    { return EncodeJSON(ie) }
+func (ie *InfixExpression) UnmarshalJSON(data []byte) error { return decodeInto(data, ie) }
+
+func (re *RangeExpression) MarshalJSON() ([]byte, error)    { return EncodeJSON(re) }
+func (re *RangeExpression) UnmarshalJSON(data []byte) error { return decodeInto(data, re) }
+
+func (ie *IndexExpression) MarshalJSON() ([]byte, error)    { return EncodeJSON(ie) }
+func (ie *IndexExpression) UnmarshalJSON(data []byte) error { return decodeInto(data, ie) }
+
+func (ae *AssignExpression) MarshalJSON() ([]byte, error)    { return EncodeJSON(ae) }
+func (ae *AssignExpression) UnmarshalJSON(data []byte) error { return decodeInto(data, ae) }
+
+func (ap *ArrayPattern) MarshalJSON() ([]byte, error)    { return EncodeJSON(ap) }
+func (ap *ArrayPattern) UnmarshalJSON(data []byte) error { return decodeInto(data, ap) }
+
+func (hp *HashPattern) MarshalJSON() ([]byte, error)    { return EncodeJSON(hp) }
+func (hp *HashPattern) UnmarshalJSON(data []byte) error { return decodeInto(data, hp) }
+
+func (he *HashPatternEntry) MarshalJSON() ([]byte, error)    { return EncodeJSON(he) }
+func (he *HashPatternEntry) UnmarshalJSON(data []byte) error { return decodeInto(data, he) }
+
+func (dp *DefaultPattern) MarshalJSON() ([]byte, error)    { return EncodeJSON(dp) }
+func (dp *DefaultPattern) UnmarshalJSON(data []byte) error { return decodeInto(data, dp) }
+
+// Sets *node to the node encoded in data, null leaves it unchanged
+func decodeInto(data []byte, node Node) error {
+	v, err := unmarshalNode(data)
+	if err != nil || !v.IsValid() {
+		return err
+	}
+
+	target := reflect.ValueOf(node)
+	if v.Type() != target.Type() {
+		return fmt.Errorf("ast.DecodeJSON: %s is not a %s", v.Type(), target.Type())
+	}
+
+	target.Elem().Set(v.Elem())
+	return nil
+}
+
+// Returns a pointer to the decoded node, the zero Value for null
+func unmarshalNode(data []byte) (reflect.Value, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return reflect.Value{}, err
+	}
+	if fields == nil {
+		return reflect.Value{}, nil
+	}
+
+	var name string
+	if err := json.Unmarshal(fields["type"], &name); err != nil {
+		return reflect.Value{}, fmt.Errorf("ast.DecodeJSON: missing node type")
+	}
+
+	t, ok := nodeTypes[name]
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("ast.DecodeJSON: unknown node type %q", name)
+	}
+
+	node := reflect.New(t)
+	for i := 0; i < t.NumField(); i++ {
+		raw, ok := fields[jsonName(t.Field(i).Name)]
+		if !ok {
+			continue
+		}
+
+		if err := unmarshalValue(raw, node.Elem().Field(i)); err != nil {
+			return reflect.Value{}, fmt.Errorf("%s.%s: %w", name, t.Field(i).Name, err)
+		}
+	}
+
+	return node, nil
+}
+
+func unmarshalValue(data []byte, field reflect.Value) error {
+	switch {
+	case isNodeType(field.Type()):
+		node, err := unmarshalNode(data)
+		if err != nil || !node.IsValid() {
+			return err
+		}
+		if !node.Type().AssignableTo(field.Type()) {
+			return fmt.Errorf("ast.DecodeJSON: %s is not a %s", node.Type(), field.Type())
+		}
+
+		field.Set(node)
+		return nil
+	case field.Kind() == reflect.Slice && isNodeType(field.Type().Elem()):
+		var elements []json.RawMessage
+		if err := json.Unmarshal(data, &elements); err != nil {
+			return err
+		}
+
+		slice := reflect.MakeSlice(field.Type(), len(elements), len(elements))
+		for i, el := range elements {
+			if err := unmarshalValue(el, slice.Index(i)); err != nil {
+				return err
+			}
+		}
+
+		field.Set(slice)
+		return nil
+	}
+
+	return json.Unmarshal(data, field.Addr().Interface())
+}
+
+// Node interfaces (Expression, Pattern...) and pointers to nodes
+func isNodeType(t reflect.Type) bool {
+	return t.Implements(nodeType) && (t.Kind() == reflect.Interface || t.Kind() == reflect.Pointer)
+}
+
+// ReturnValue -> returnValue
+func jsonName(field string) string {
+	r, size := utf8.DecodeRuneInString(field)
+	return string(unicode.ToLower(r)) + field[size:]
+}
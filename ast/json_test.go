@@ -0,0 +1,135 @@
+package ast_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jeremi-traverse/monkey/ast"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	inputs := []string{
+		"let x = 5; const y = -1.5; return x + y * 2;",
+		"let [a, {b: c = 1, d}, ...e] = x; const {f, ...g} = h;",
+		"return; while (true) { break; } for (;;) { continue; }",
+		"for (let i = 0; i < 9223372036854775808; i += 1) { h[i] = !false; }",
+		"for (k, v in 0..=10) { a = b = (c - d) - e; } // comment",
+	}
+
+	for _, input := range inputs {
+		program := parse(t, input)
+
+		data, err := ast.EncodeJSON(program)
+		if err != nil {
+			t.Fatalf("%q: EncodeJSON failed: %s", input, err)
+		}
+
+		if !json.Valid(data) {
+			t.Fatalf("%q: invalid JSON: %s", input, data)
+		}
+
+		node, err := ast.DecodeJSON(data)
+		if err != nil {
+			t.Fatalf("%q: DecodeJSON failed: %s", input, err)
+		}
+
+		if node.String() != program.String() {
+			t.Errorf("round trip changed the program.\nexpected=%q\ngot=     %q",
+				program.String(), node.String())
+		}
+
+		if equal, path := ast.Equal(program, node, ast.EqualOptions{}); !equal {
+			t.Errorf("round trip changed the tree at %s", path)
+		}
+
+		// Positions and comments are kept too
+		again, err := ast.EncodeJSON(node)
+		if err != nil {
+			t.Fatalf("%q: EncodeJSON failed: %s", input, err)
+		}
+		if string(again) != string(data) {
+			t.Errorf("round trip changed the JSON.\nexpected=%s\ngot=     %s", data, again)
+		}
+	}
+}
+
+// encoding/json reads and writes the nodes through EncodeJSON and DecodeJSON
+func TestJSONMarshaler(t *testing.T) {
+	program := parse(t, "let [a, b] = x < 1; // c")
+
+	data, err := json.Marshal(program)
+	if err != nil {
+		t.Fatalf("json.Marshal failed: %s", err)
+	}
+	if !strings.HasPrefix(string(data), `{"type":"Program",`) {
+		t.Fatalf("node type missing: %s", data)
+	}
+
+	var decoded ast.Program
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal failed: %s", err)
+	}
+	if equal, path := ast.Equal(program, &decoded, ast.EqualOptions{}); !equal {
+		t.Errorf("round trip changed the tree at %s", path)
+	}
+
+	// Inside other values too
+	wrapped := struct{ Name *ast.Identifier }{}
+	if err := json.Unmarshal([]byte(`{"Name":{"type":"Identifier","value":"x"}}`), &wrapped); err != nil {
+		t.Fatalf("json.Unmarshal failed: %s", err)
+	}
+	if wrapped.Name == nil || wrapped.Name.Value != "x" {
+		t.Errorf("wrong identifier. got=%+v", wrapped.Name)
+	}
+
+	var ident ast.Identifier
+	err = json.Unmarshal(data, &ident)
+	if err == nil || err.Error() != "ast.DecodeJSON: *ast.Program is not a *ast.Identifier" {
+		t.Errorf("wrong error decoding a Program into an Identifier. got=%v", err)
+	}
+}
+
+func TestEncodeJSON(t *testing.T) {
+	program := parse(t, "-x")
+	exp := program.Statements[0].(*ast.ExpressionStatement).Expression
+
+	data, err := ast.EncodeJSON(exp)
+	if err != nil {
+		t.Fatalf("EncodeJSON failed: %s", err)
+	}
+
+	expected := `{"type":"PrefixExpression",` +
+		`"token":{"type":"-","literal":"-","line":1,"column":1},` +
+		`"operator":"-",` +
+		`"right":{"type":"Identifier","token":{"type":"IDENT","literal":"x","line":1,"column":2},"value":"x"}}`
+
+	if string(data) != expected {
+		t.Fatalf("wrong JSON.\nexpected=%s\ngot=     %s", expected, data)
+	}
+}
+
+func TestDecodeJSONErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{"type":"Unknown"}`, `ast.DecodeJSON: unknown node type "Unknown"`},
+		{`{"value":"x"}`, "ast.DecodeJSON: missing node type"},
+		{`{"type":"ExpressionStatement","expression":{"type":"BreakStatement"}}`,
+			"ExpressionStatement.Expression: ast.DecodeJSON: *ast.BreakStatement is not a ast.Expression"},
+	}
+
+	for _, tt := range tests {
+		_, err := ast.DecodeJSON([]byte(tt.input))
+		if err == nil || err.Error() != tt.expected {
+			t.Errorf("%s: wrong error. expected=%q, got=%v", tt.input, tt.expected, err)
+		}
+	}
+}
+
+func TestEncodeJSONUnknownNode(t *testing.T) {
+	if _, err := ast.EncodeJSON(unknownNode{}); err == nil {
+		t.Fatalf("EncodeJSON didn't fail on an unknown node type")
+	}
+}
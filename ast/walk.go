@@ -0,0 +1,137 @@
+package ast
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Walk calls Visit with each node, the children of the node are then
+// walked with the returned Visitor, or skipped when it's nil.
+// Visit(nil) is called once they're done
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk visits node then its children, depth first and in source order.
+// Nil children (i.e. an empty for clause) are skipped
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	switch n := node.(type) {
+	case *Program:
+		walkStatements(v, n.Statements)
+
+	// Statements
+	case *LetStatement:
+		walkIfNotNil(v, n.Name)
+		walkIfNotNil(v, n.Value)
+	case *ConstStatement:
+		walkIfNotNil(v, n.Name)
+		walkIfNotNil(v, n.Value)
+	case *ReturnStatement:
+		walkIfNotNil(v, n.ReturnValue)
+	case *ExpressionStatement:
+		walkIfNotNil(v, n.Expression)
+	case *BlockStatement:
+		walkStatements(v, n.Statements)
+	case *WhileStatement:
+		walkIfNotNil(v, n.Condition)
+		walkIfNotNil(v, n.Body)
+	case *ForStatement:
+		walkIfNotNil(v, n.Init)
+		walkIfNotNil(v, n.Condition)
+		walkIfNotNil(v, n.Post)
+		walkIfNotNil(v, n.Body)
+	case *ForInStatement:
+		walkIfNotNil(v, n.Key)
+		walkIfNotNil(v, n.Value)
+		walkIfNotNil(v, n.Iterable)
+		walkIfNotNil(v, n.Body)
+	case *BreakStatement, *ContinueStatement:
+		// nothing to do
+
+	// Expressions
+	case *Identifier, *IntegerLiteral, *BigIntegerLiteral, *FloatLiteral, *Boolean:
+		// nothing to do
+	case *PrefixExpression:
+		walkIfNotNil(v, n.Right)
+	case *InfixExpression:
+		walkIfNotNil(v, n.Left)
+		walkIfNotNil(v, n.Right)
+	case *RangeExpression:
+		walkIfNotNil(v, n.Start)
+		walkIfNotNil(v, n.End)
+	case *IndexExpression:
+		walkIfNotNil(v, n.Left)
+		walkIfNotNil(v, n.Index)
+	case *AssignExpression:
+		walkIfNotNil(v, n.Target)
+		walkIfNotNil(v, n.Value)
+
+	// Patterns
+	case *ArrayPattern:
+		for _, el := range n.Elements {
+			walkIfNotNil(v, el)
+		}
+		walkIfNotNil(v, n.Rest)
+	case *HashPattern:
+		for _, entry := range n.Entries {
+			walkIfNotNil(v, entry)
+		}
+		walkIfNotNil(v, n.Rest)
+	case *HashPatternEntry:
+		walkIfNotNil(v, n.Key)
+		walkIfNotNil(v, n.Value)
+	case *DefaultPattern:
+		walkIfNotNil(v, n.Target)
+		walkIfNotNil(v, n.Default)
+
+	default:
+		panic(fmt.Sprintf("ast.Walk: unexpected node type %T", n))
+	}
+
+	v.Visit(nil)
+}
+
+func walkStatements(v Visitor, statements []Statement) {
+	for _, s := range statements {
+		walkIfNotNil(v, s)
+	}
+}
+
+// Optional fields hold either a nil interface or a typed nil pointer
+// (i.e. a Rest that isn't set), both are skipped
+func walkIfNotNil(v Visitor, node Node) {
+	if isNil(node) {
+		return
+	}
+
+	Walk(v, node)
+}
+
+func isNil(node Node) bool {
+	if node == nil {
+		return true
+	}
+
+	value := reflect.ValueOf(node)
+	return value.Kind() == reflect.Pointer && value.IsNil()
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+
+	return nil
+}
+
+// Inspect walks the tree under node with f, the children of a node are
+// only walked when f returns true for it and are followed by f(nil)
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
@@ -0,0 +1,140 @@
+package ast_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jeremi-traverse/monkey/ast"
+	"github.com/jeremi-traverse/monkey/lexer"
+	"github.com/jeremi-traverse/monkey/parser"
+)
+
+func TestInspect(t *testing.T) {
+	input := `
+	let [a, {b: c = 1}, ...d] = x;
+	const y = -1.5;
+	return;
+	while (true) { break; }
+	for (let i = 0; i < 9223372036854775808; i += 1) { continue; }
+	for (k, v in 0..10) { h[k] = v; }
+	`
+
+	expected := []string{
+		"*ast.Program",
+		"*ast.LetStatement",
+		"*ast.ArrayPattern", "*ast.Identifier",
+		"*ast.HashPattern", "*ast.HashPatternEntry", "*ast.Identifier", "*ast.DefaultPattern", "*ast.Identifier", "*ast.IntegerLiteral",
+		"*ast.Identifier", "*ast.Identifier",
+		"*ast.ConstStatement", "*ast.Identifier", "*ast.PrefixExpression", "*ast.FloatLiteral",
+		"*ast.ReturnStatement",
+		"*ast.WhileStatement", "*ast.Boolean", "*ast.BlockStatement", "*ast.BreakStatement",
+		"*ast.ForStatement",
+		"*ast.LetStatement", "*ast.Identifier", "*ast.IntegerLiteral",
+		"*ast.InfixExpression", "*ast.Identifier", "*ast.BigIntegerLiteral",
+		"*ast.AssignExpression", "*ast.Identifier", "*ast.IntegerLiteral",
+		"*ast.BlockStatement", "*ast.ContinueStatement",
+		"*ast.ForInStatement", "*ast.Identifier", "*ast.Identifier",
+		"*ast.RangeExpression", "*ast.IntegerLiteral", "*ast.IntegerLiteral",
+		"*ast.BlockStatement", "*ast.ExpressionStatement", "*ast.AssignExpression",
+		"*ast.IndexExpression", "*ast.Identifier", "*ast.Identifier", "*ast.Identifier",
+	}
+
+	program := parse(t, input)
+
+	visited := []string{}
+	ast.Inspect(program, func(n ast.Node) bool {
+		if n != nil {
+			visited = append(visited, fmt.Sprintf("%T", n))
+		}
+		return true
+	})
+
+	if strings.Join(visited, " ") != strings.Join(expected, " ") {
+		t.Fatalf("wrong visit order.\nexpected=%v\ngot=     %v", expected, visited)
+	}
+}
+
+func TestInspectSkipsChildren(t *testing.T) {
+	program := parse(t, "1 + 2 * 3; x;")
+
+	identifiers, integers := 0, 0
+	ast.Inspect(program, func(n ast.Node) bool {
+		switch n.(type) {
+		case *ast.InfixExpression:
+			return false
+		case *ast.IntegerLiteral:
+			integers++
+		case *ast.Identifier:
+			identifiers++
+		}
+		return true
+	})
+
+	if integers != 0 || identifiers != 1 {
+		t.Fatalf("children of an infix expression visited. got integers=%d, identifiers=%d",
+			integers, identifiers)
+	}
+}
+
+// Records the depth of each node, Visit(nil) closes a level
+type depthVisitor struct {
+	depth  *int
+	depths *[]string
+}
+
+func (v depthVisitor) Visit(n ast.Node) ast.Visitor {
+	if n == nil {
+		*v.depth--
+		return nil
+	}
+
+	*v.depths = append(*v.depths, fmt.Sprintf("%d:%s", *v.depth, n))
+	*v.depth++
+	return v
+}
+
+func TestWalk(t *testing.T) {
+	program := parse(t, "-a + b")
+
+	depth, depths := 0, []string{}
+	ast.Walk(depthVisitor{&depth, &depths}, program)
+
+	expected := []string{"0:((-a) + b)", "1:((-a) + b)", "2:((-a) + b)", "3:(-a)", "4:a", "3:b"}
+	if strings.Join(depths, " ") != strings.Join(expected, " ") {
+		t.Fatalf("wrong depths. expected=%v, got=%v", expected, depths)
+	}
+
+	if depth != 0 {
+		t.Fatalf("Visit(nil) not called after each node's children. depth=%d", depth)
+	}
+}
+
+type unknownNode struct{}
+
+func (unknownNode) TokenLiteral() string { return "" }
+func (unknownNode) String() string       { return "" }
+
+func TestWalkUnknownNode(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("Walk didn't panic on an unknown node type")
+		}
+	}()
+
+	ast.Inspect(unknownNode{}, func(ast.Node) bool { return true })
+}
+
+func parse(t *testing.T, input string) *ast.Program {
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+
+	for _, msg := range p.Errors() {
+		t.Errorf("parser error: %s", msg)
+	}
+	if len(p.Errors()) != 0 {
+		t.FailNow()
+	}
+
+	return program
+}
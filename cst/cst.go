@@ -0,0 +1,53 @@
+// Package cst holds the concrete syntax tree of a Monkey program:
+// every token of the source, trivia included, grouped by the syntax
+// node they make up. It's made by parser.ParseConcrete for tools that
+// need to rewrite a file without touching the rest of it
+package cst
+
+import (
+	"strings"
+
+	"github.com/jeremi-traverse/monkey/token"
+)
+
+// A Node is either a token (a leaf) or a sequence of nodes
+type Node struct {
+	// Type of the matching ast node, i.e. "LetStatement" or "InfixExpression",
+	// "GroupedExpression" for (...) and "Bad" for what failed to parse.
+	// Empty for a token
+	Kind string
+
+	Token    *token.Token // Only set for a token
+	Children []*Node
+}
+
+func (n *Node) IsToken() bool { return n.Token != nil }
+
+// Tokens returns the tokens of the node, in source order
+func (n *Node) Tokens() []token.Token {
+	var tokens []token.Token
+	n.walk(func(tok *token.Token) { tokens = append(tokens, *tok) })
+	return tokens
+}
+
+// String returns the source text of the node, trivia included.
+// The String of the root is the parsed input byte for byte
+func (n *Node) String() string {
+	var out strings.Builder
+	n.walk(func(tok *token.Token) {
+		out.WriteString(tok.Leading)
+		out.WriteString(tok.Literal)
+	})
+	return out.String()
+}
+
+func (n *Node) walk(f func(*token.Token)) {
+	if n.IsToken() {
+		f(n.Token)
+		return
+	}
+
+	for _, child := range n.Children {
+		child.walk(f)
+	}
+}
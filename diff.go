@@ -0,0 +1,229 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Lines of context around each change
+const diffContext = 3
+
+type diffLine struct {
+	kind byte   // ' ', '-' or '+'
+	text string // with its \n, the last line of a file can miss it
+}
+
+// Returns a unified diff of a and b, empty when they are equal
+func unifiedDiff(filename string, a, b []byte) []byte {
+	if bytes.Equal(a, b) {
+		return nil
+	}
+
+	lines := diffLines(splitLines(a), splitLines(b))
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "--- %s.orig\n+++ %s\n", filename, filename)
+
+	for start := 0; start < len(lines); {
+		// Find the next change
+		for start < len(lines) && lines[start].kind == ' ' {
+			start++
+		}
+		if start == len(lines) {
+			break
+		}
+
+		// Extend the hunk while changes are close enough to share context
+		end := start
+		for i := start; i < len(lines); i++ {
+			if lines[i].kind != ' ' {
+				end = i + 1
+			} else if i-end >= 2*diffContext {
+				break
+			}
+		}
+
+		from, to := max(start-diffContext, 0), min(end+diffContext, len(lines))
+		writeHunk(&out, lines, from, to)
+		start = to
+	}
+
+	return out.Bytes()
+}
+
+func writeHunk(out *bytes.Buffer, lines []diffLine, from, to int) {
+	// Line numbers of the hunk start in a and b
+	aLine, bLine := 1, 1
+	for _, l := range lines[:from] {
+		if l.kind != '+' {
+			aLine++
+		}
+		if l.kind != '-' {
+			bLine++
+		}
+	}
+
+	aCount, bCount := 0, 0
+	for _, l := range lines[from:to] {
+		if l.kind != '+' {
+			aCount++
+		}
+		if l.kind != '-' {
+			bCount++
+		}
+	}
+
+	fmt.Fprintf(out, "@@ -%s +%s @@\n", hunkRange(aLine, aCount), hunkRange(bLine, bCount))
+	for _, l := range lines[from:to] {
+		out.WriteByte(l.kind)
+		out.WriteString(l.text)
+		if !strings.HasSuffix(l.text, "\n") {
+			out.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start-1)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// Lines keep their \n so that a missing one at the end of the file
+// is a difference
+func splitLines(src []byte) []string {
+	lines := strings.SplitAfter(string(src), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// Edit script turning a into b, with as few - and + lines as possible.
+// Uses Myers' linear space algorithm ("An O(ND) Difference Algorithm
+// and Its Variations", 1986): memory stays linear in the number of
+// lines where a table of the common subsequences would be quadratic
+func diffLines(a, b []string) []diffLine {
+	d := &differ{a: a, b: b}
+	d.compare(0, len(a), 0, len(b))
+
+	return d.lines
+}
+
+type differ struct {
+	a, b   []string
+	lines  []diffLine
+	vf, vb []int // furthest x on each diagonal, forward and backward
+}
+
+// Appends the edit script of a[aLo:aHi] into b[bLo:bHi]
+func (d *differ) compare(aLo, aHi, bLo, bHi int) {
+	// Common prefix and suffix
+	for aLo < aHi && bLo < bHi && d.a[aLo] == d.b[bLo] {
+		d.lines = append(d.lines, diffLine{' ', d.a[aLo]})
+		aLo++
+		bLo++
+	}
+	suffix := 0
+	for aLo < aHi-suffix && bLo < bHi-suffix && d.a[aHi-suffix-1] == d.b[bHi-suffix-1] {
+		suffix++
+	}
+	aHi -= suffix
+	bHi -= suffix
+
+	switch {
+	case aLo == aHi:
+		for _, line := range d.b[bLo:bHi] {
+			d.lines = append(d.lines, diffLine{'+', line})
+		}
+	case bLo == bHi:
+		for _, line := range d.a[aLo:aHi] {
+			d.lines = append(d.lines, diffLine{'-', line})
+		}
+	default:
+		x, y, u, v := d.middleSnake(aLo, aHi, bLo, bHi)
+
+		d.compare(aLo, x, bLo, y)
+		for _, line := range d.a[x:u] {
+			d.lines = append(d.lines, diffLine{' ', line})
+		}
+		d.compare(u, aHi, v, bHi)
+	}
+
+	for _, line := range d.a[aHi : aHi+suffix] {
+		d.lines = append(d.lines, diffLine{' ', line})
+	}
+}
+
+// Finds the snake (run of equal lines) from (x, y) to (u, v) in the
+// middle of a shortest edit script, by searching from both ends until
+// the paths overlap. a[aLo:aHi] and b[bLo:bHi] are both non empty
+func (d *differ) middleSnake(aLo, aHi, bLo, bHi int) (x, y, u, v int) {
+	n, m := aHi-aLo, bHi-bLo
+	delta := n - m
+	maxD := (n + m + 1) / 2
+
+	// Diagonal k is at index k+offset, k goes from -maxD-1 to maxD+1
+	offset := maxD + 1
+	size := 2*maxD + 3
+	if len(d.vf) < size {
+		d.vf, d.vb = make([]int, size), make([]int, size)
+	}
+	vf, vb := d.vf, d.vb
+	vf[offset+1], vb[offset+1] = 0, 0
+
+	for D := 0; D <= maxD; D++ {
+		for k := -D; k <= D; k += 2 {
+			// Down (insertion) or right (deletion), whichever goes further
+			var x int
+			if k == -D || (k != D && vf[offset+k-1] < vf[offset+k+1]) {
+				x = vf[offset+k+1]
+			} else {
+				x = vf[offset+k-1] + 1
+			}
+			y := x - k
+			x0, y0 := x, y
+			for x < n && y < m && d.a[aLo+x] == d.b[bLo+y] {
+				x++
+				y++
+			}
+			vf[offset+k] = x
+
+			// Overlaps the backward path of the previous round
+			if kb := delta - k; delta%2 != 0 && kb >= -(D-1) && kb <= D-1 && x+vb[offset+kb] >= n {
+				return aLo + x0, bLo + y0, aLo + x, bLo + y
+			}
+		}
+
+		for k := -D; k <= D; k += 2 {
+			// Same from the ends, x and y count the lines from the end
+			var x int
+			if k == -D || (k != D && vb[offset+k-1] < vb[offset+k+1]) {
+				x = vb[offset+k+1]
+			} else {
+				x = vb[offset+k-1] + 1
+			}
+			y := x - k
+			x0, y0 := x, y
+			for x < n && y < m && d.a[aHi-1-x] == d.b[bHi-1-y] {
+				x++
+				y++
+			}
+			vb[offset+k] = x
+
+			if kf := delta - k; delta%2 == 0 && kf >= -D && kf <= D && x+vf[offset+kf] >= n {
+				return aHi - x, bHi - y, aHi - x0, bHi - y0
+			}
+		}
+	}
+
+	// Unreachable, the paths meet by round maxD
+	panic("diff: no middle snake")
+}
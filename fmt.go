@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jeremi-traverse/monkey/printer"
+)
+
+// monkey fmt [-w] [-d] [files...]
+// Formats the files, or the standard input when there is none.
+// Returns the exit code: 2 when a file can't be read or parsed
+func runFmt(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	write := flags.Bool("w", false, "write result to the source file instead of stdout")
+	diff := flags.Bool("d", false, "display diffs instead of rewriting files")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: monkey fmt [-w] [-d] [files...]")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if flags.NArg() == 0 {
+		if *write {
+			fmt.Fprintln(stderr, "monkey fmt: cannot use -w with standard input")
+			return 2
+		}
+
+		src, err := io.ReadAll(stdin)
+		if err != nil {
+			fmt.Fprintf(stderr, "monkey fmt: %s\n", err)
+			return 2
+		}
+
+		return formatFile("<standard input>", src, false, *diff, stdout, stderr)
+	}
+
+	exitCode := 0
+	for _, filename := range flags.Args() {
+		src, err := os.ReadFile(filename)
+		if err != nil {
+			fmt.Fprintf(stderr, "monkey fmt: %s\n", err)
+			exitCode = 2
+			continue
+		}
+
+		if code := formatFile(filename, src, *write, *diff, stdout, stderr); code != 0 {
+			exitCode = code
+		}
+	}
+
+	return exitCode
+}
+
+func formatFile(filename string, src []byte, write, diff bool, stdout, stderr io.Writer) int {
+	formatted, err := printer.Format(src)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s:\n%s\n", filename, err)
+		return 2
+	}
+
+	if write && !bytes.Equal(src, formatted) {
+		if err := os.WriteFile(filename, formatted, 0o644); err != nil {
+			fmt.Fprintf(stderr, "monkey fmt: %s\n", err)
+			return 2
+		}
+	}
+
+	if diff {
+		stdout.Write(unifiedDiff(filename, src, formatted))
+	}
+
+	if !write && !diff {
+		stdout.Write(formatted)
+	}
+
+	return 0
+}
@@ -1,27 +1,106 @@
 package lexer
 
 import (
+	"io"
+	"strings"
+
 	"github.com/jeremi-traverse/monkey/token"
 )
 
 // Making the current char a byte makes our lexer supports only ASCII characters
 type Lexer struct {
-	input           string // source code
+	input           string // source code, only the part not lexed yet when read from a reader
 	currentPosition int    // current position in input (points to current char)
 	nextPosition    int    // current reading position in input (position + 1, next char)
 	currentChar     byte   // current char being examined
+	line            int    // line of the current char
+	column          int    // column of the current char
+
+	comments []token.Token // comments skipped so far, not kept for a reader
+	trivia   bool          // keep the text skipped before each token
+
+	reader io.Reader // rest of the source, nil once read entirely
+	chunk  []byte    // read buffer of the reader
+	err    error     // error returned by the reader
 }
 
+// Bytes read from a reader at once
+const chunkSize = 4096
+
 func New(input string) *Lexer {
-	l := &Lexer{input: input}
+	l := &Lexer{input: input, line: 1}
 
 	// Read the first character of the lexer's input
 	l.readChar()
 	return l
 }
 
+// NewReader returns a lexer reading its input from r as tokens are
+// requested, the tokens are the same as New's for the same input.
+// Only the current token and the next chunk of r are kept in memory,
+// so Comments stays empty. A read error ends the input like EOF,
+// Err reports it
+func NewReader(r io.Reader) *Lexer {
+	l := &Lexer{line: 1, reader: r, chunk: make([]byte, chunkSize)}
+
+	l.readChar()
+	return l
+}
+
+// Err returns the error the reader of NewReader failed with, if any
+func (l *Lexer) Err() error {
+	return l.err
+}
+
+// Reads from the reader until input holds the byte at position i
+// or the reader is done
+func (l *Lexer) fill(i int) {
+	for l.reader != nil && i >= len(l.input) {
+		n, err := l.reader.Read(l.chunk)
+		l.input += string(l.chunk[:n])
+
+		if err != nil {
+			if err != io.EOF {
+				l.err = err
+			}
+			l.reader = nil
+		}
+	}
+}
+
+// Drops the input lexed so far, called between tokens so that no
+// position into input is kept. Only worth it once a chunk is lexed
+func (l *Lexer) discard() {
+	if l.chunk == nil || l.currentPosition < chunkSize {
+		return
+	}
+
+	n := min(l.currentPosition, len(l.input))
+	// Copy so the lexed input can be freed
+	l.input = strings.Clone(l.input[n:])
+	l.currentPosition -= n
+	l.nextPosition -= n
+}
+
+// NewWithTrivia returns a lexer that keeps the whitespace and comments
+// before each token in its Leading field, the EOF token holds the end
+// of the input. Concatenating Leading and Literal of every token up to
+// EOF gives back the input byte for byte
+func NewWithTrivia(input string) *Lexer {
+	l := New(input)
+	l.trivia = true
+	return l
+}
+
 // consume the current char
 func (l *Lexer) readChar() {
+	if l.currentChar == '\n' {
+		l.line += 1
+		l.column = 0
+	}
+	l.column += 1
+
+	l.fill(l.nextPosition)
 	if l.nextPosition >= len(l.input) {
 		l.currentChar = 0
 	} else {
@@ -36,11 +115,21 @@ func (l *Lexer) readChar() {
 
 // Returns a token containing information about the current char
 // and advance the lexer to the next char
-func (l *Lexer) NextToken() token.Token {
-	var tok token.Token
+func (l *Lexer) NextToken() (tok token.Token) {
+	l.discard()
 
+	// Past the end once EOF has been returned
+	start := min(l.currentPosition, len(l.input))
 	l.skipWhiteSpace()
 
+	if l.trivia {
+		leading := l.input[start:min(l.currentPosition, len(l.input))]
+		defer func() { tok.Leading = leading }()
+	}
+
+	// Position of the first char of the token
+	line, column := l.line, l.column
+
 	switch l.currentChar {
 	case '=':
 		if l.peekChar() == '=' {
@@ -54,6 +143,8 @@ func (l *Lexer) NextToken() token.Token {
 		}
 	case ';':
 		tok = newToken(token.SEMICOLON, l.currentChar)
+	case ':':
+		tok = newToken(token.COLON, l.currentChar)
 	case '(':
 		tok = newToken(token.LPAREN, l.currentChar)
 	case ')':
@@ -62,12 +153,26 @@ func (l *Lexer) NextToken() token.Token {
 		tok = newToken(token.LBRACE, l.currentChar)
 	case '}':
 		tok = newToken(token.RBRACE, l.currentChar)
+	case '[':
+		tok = newToken(token.LBRACKET, l.currentChar)
+	case ']':
+		tok = newToken(token.RBRACKET, l.currentChar)
 	case ',':
 		tok = newToken(token.COMMA, l.currentChar)
 	case '+':
-		tok = newToken(token.PLUS, l.currentChar)
+		if l.peekChar() == '=' {
+			l.readChar()
+			tok = token.Token{Type: token.PLUS_ASSIGN, Literal: "+="}
+		} else {
+			tok = newToken(token.PLUS, l.currentChar)
+		}
 	case '-':
-		tok = newToken(token.MINUS, l.currentChar)
+		if l.peekChar() == '=' {
+			l.readChar()
+			tok = token.Token{Type: token.MINUS_ASSIGN, Literal: "-="}
+		} else {
+			tok = newToken(token.MINUS, l.currentChar)
+		}
 	case '!':
 		if l.peekChar() == '=' {
 			currentCharSnapshot := l.currentChar
@@ -79,32 +184,65 @@ func (l *Lexer) NextToken() token.Token {
 			tok = newToken(token.BANG, l.currentChar)
 		}
 	case '*':
-		tok = newToken(token.ASTERISK, l.currentChar)
+		if l.peekChar() == '=' {
+			l.readChar()
+			tok = token.Token{Type: token.ASTERISK_ASSIGN, Literal: "*="}
+		} else {
+			tok = newToken(token.ASTERISK, l.currentChar)
+		}
 	case '/':
-		tok = newToken(token.SLASH, l.currentChar)
+		if l.peekChar() == '=' {
+			l.readChar()
+			tok = token.Token{Type: token.SLASH_ASSIGN, Literal: "/="}
+		} else {
+			tok = newToken(token.SLASH, l.currentChar)
+		}
+	case '.':
+		if l.peekChar() == '.' {
+			l.readChar()
+			if l.peekChar() == '=' {
+				l.readChar()
+				tok = token.Token{Type: token.RANGE_EQ, Literal: "..="}
+			} else if l.peekChar() == '.' {
+				l.readChar()
+				tok = token.Token{Type: token.ELLIPSIS, Literal: "..."}
+			} else {
+				tok = token.Token{Type: token.RANGE, Literal: ".."}
+			}
+		} else {
+			tok = newToken(token.ILLEGAL, l.currentChar)
+		}
 	case '<':
 		tok = newToken(token.LT, l.currentChar)
 	case '>':
 		tok = newToken(token.GT, l.currentChar)
 	case 0:
-		tok.Type = token.EOF
-		tok.Literal = ""
+		if l.currentPosition < len(l.input) {
+			// A NUL byte in the input
+			tok = l.illegalToken()
+		} else {
+			tok.Type = token.EOF
+			tok.Literal = ""
+		}
 	default:
 		if isLetter(l.currentChar) {
 			tok.Literal = l.readIndentifier()
 			tok.Type = token.LookupIdent(tok.Literal)
+			tok.Line, tok.Column = line, column
 			// early returns to skip the readChar after the switch statement
 			return tok
 		} else if isDigit(l.currentChar) {
-			tok.Literal = l.readNumber()
-			tok.Type = token.INT
+			tok.Literal, tok.Type = l.readNumber()
+			tok.Line, tok.Column = line, column
 			// early returns to skip the readChar after the switch statement
 			return tok
 		} else {
-			tok = newToken(token.ILLEGAL, l.currentChar)
+			tok = l.illegalToken()
 		}
 	}
 
+	tok.Line, tok.Column = line, column
+
 	l.readChar()
 	return tok
 }
@@ -113,6 +251,12 @@ func newToken(tokenType token.TokenType, literal byte) token.Token {
 	return token.Token{Type: tokenType, Literal: string(literal)}
 }
 
+// The literal is the byte as is, string(byte) would encode
+// a non ASCII byte as a 2 bytes rune
+func (l *Lexer) illegalToken() token.Token {
+	return token.Token{Type: token.ILLEGAL, Literal: l.input[l.currentPosition:l.nextPosition]}
+}
+
 func (l *Lexer) readIndentifier() string {
 	initialPosition := l.currentPosition
 
@@ -123,17 +267,54 @@ func (l *Lexer) readIndentifier() string {
 	return l.input[initialPosition:l.currentPosition]
 }
 
-func (l *Lexer) readNumber() string {
+// Reads an integer or a float, i.e. 42, 0xFF, 0o17, 0b1010, 1_000_000, 3.14, 1e-9
+// Malformed literals (0x, 1__0) are returned as is for the parser to report
+func (l *Lexer) readNumber() (string, token.TokenType) {
 	initialPosition := l.currentPosition
+	var tokenType token.TokenType = token.INT
 
-	for isDigit(l.currentChar) {
+	if l.currentChar == '0' && isBasePrefix(l.peekChar()) {
+		// Consume the prefix
 		l.readChar()
+		l.readChar()
+
+		for isHexDigit(l.currentChar) || l.currentChar == '_' {
+			l.readChar()
+		}
+
+		return l.input[initialPosition:l.currentPosition], tokenType
 	}
 
-	return l.input[initialPosition:l.currentPosition]
+	l.readDigits()
+
+	// The fraction needs a digit after the dot
+	if l.currentChar == '.' && isDigit(l.peekChar()) {
+		tokenType = token.FLOAT
+		l.readChar()
+		l.readDigits()
+	}
+
+	if l.currentChar == 'e' || l.currentChar == 'E' {
+		tokenType = token.FLOAT
+		l.readChar()
+		if l.currentChar == '+' || l.currentChar == '-' {
+			l.readChar()
+		}
+		l.readDigits()
+	}
+
+	return l.input[initialPosition:l.currentPosition], tokenType
+}
+
+// Reads decimal digits and their '_' separators
+func (l *Lexer) readDigits() {
+	for isDigit(l.currentChar) || l.currentChar == '_' {
+		l.readChar()
+	}
 }
 
 func (l *Lexer) peekChar() byte {
+	l.fill(l.nextPosition)
 	if l.nextPosition >= len(l.input) {
 		return 0
 	} else {
@@ -149,8 +330,52 @@ func isDigit(c byte) bool {
 	return '0' <= c && c <= '9'
 }
 
+func isHexDigit(c byte) bool {
+	return isDigit(c) || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+// x for hexadecimal, o for octal and b for binary
+func isBasePrefix(c byte) bool {
+	switch c {
+	case 'x', 'X', 'o', 'O', 'b', 'B':
+		return true
+	}
+
+	return false
+}
+
+// Skips whitespaces and comments, the comments are kept aside
 func (l *Lexer) skipWhiteSpace() {
-	for l.currentChar == ' ' || l.currentChar == '\t' || l.currentChar == '\n' || l.currentChar == '\r' {
+	for {
+		switch {
+		case l.currentChar == ' ' || l.currentChar == '\t' || l.currentChar == '\n' || l.currentChar == '\r':
+			l.readChar()
+		case l.currentChar == '/' && l.peekChar() == '/':
+			l.readComment()
+		default:
+			return
+		}
+	}
+}
+
+// Reads a // comment up to the end of the line
+func (l *Lexer) readComment() {
+	tok := token.Token{Type: token.COMMENT, Line: l.line, Column: l.column}
+	initialPosition := l.currentPosition
+
+	for l.currentChar != '\n' && l.currentChar != 0 {
 		l.readChar()
 	}
+
+	tok.Literal = strings.TrimRight(l.input[initialPosition:l.currentPosition], " \t\r")
+	// A reader can be read for ever, its comments would pile up
+	if l.chunk == nil {
+		l.comments = append(l.comments, tok)
+	}
+}
+
+// Comments returns the comments read so far, in source order.
+// Lexers from NewReader don't keep them
+func (l *Lexer) Comments() []token.Token {
+	return l.comments
 }
@@ -1,7 +1,11 @@
 package lexer
 
 import (
+	"errors"
+	"io"
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/jeremi-traverse/monkey/token"
 )
@@ -24,6 +28,14 @@ func TestNextTok(t *testing.T) {
 	10 == 10
 
 	9 != 10
+
+	while for break continue const
+
+	for (k, v in 0..=10) . 
+
+	x += 1 -= *= /= arr[0]
+
+	{a: b, ...c}
 	`
 
 	tests := []struct {
@@ -85,6 +97,41 @@ func TestNextTok(t *testing.T) {
 		{token.INT, "9"},
 		{token.NOT_EQ, "!="},
 		{token.INT, "10"},
+		{token.WHILE, "while"},
+		{token.FOR, "for"},
+		{token.BREAK, "break"},
+		{token.CONTINUE, "continue"},
+		{token.CONST, "const"},
+		{token.FOR, "for"},
+		{token.LPAREN, "("},
+		{token.IDENT, "k"},
+		{token.COMMA, ","},
+		{token.IDENT, "v"},
+		{token.IN, "in"},
+		{token.INT, "0"},
+		{token.RANGE_EQ, "..="},
+		{token.INT, "10"},
+		{token.RPAREN, ")"},
+		{token.ILLEGAL, "."},
+		{token.IDENT, "x"},
+		{token.PLUS_ASSIGN, "+="},
+		{token.INT, "1"},
+		{token.MINUS_ASSIGN, "-="},
+		{token.ASTERISK_ASSIGN, "*="},
+		{token.SLASH_ASSIGN, "/="},
+		{token.IDENT, "arr"},
+		{token.LBRACKET, "["},
+		{token.INT, "0"},
+		{token.RBRACKET, "]"},
+		{token.LBRACE, "{"},
+		{token.IDENT, "a"},
+		{token.COLON, ":"},
+		{token.IDENT, "b"},
+		{token.COMMA, ","},
+		{token.ELLIPSIS, "..."},
+		{token.IDENT, "c"},
+		{token.RBRACE, "}"},
+		{token.EOF, ""},
 	}
 
 	l := New(input)
@@ -101,3 +148,244 @@ func TestNextTok(t *testing.T) {
 		}
 	}
 }
+
+func TestNumbers(t *testing.T) {
+	input := `42 3.14 1e-9 2.5E+3 7e2 1..5 0.5;
+	0xFF 0o17 0b1010 1_000_000 1_000.5 0xzz 1__0`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.INT, "42"},
+		{token.FLOAT, "3.14"},
+		{token.FLOAT, "1e-9"},
+		{token.FLOAT, "2.5E+3"},
+		{token.FLOAT, "7e2"},
+		{token.INT, "1"},
+		{token.RANGE, ".."},
+		{token.INT, "5"},
+		{token.FLOAT, "0.5"},
+		{token.SEMICOLON, ";"},
+		{token.INT, "0xFF"},
+		{token.INT, "0o17"},
+		{token.INT, "0b1010"},
+		{token.INT, "1_000_000"},
+		{token.FLOAT, "1_000.5"},
+		{token.INT, "0x"},
+		{token.IDENT, "zz"},
+		{token.INT, "1__0"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got %q", i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got %q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestPositions(t *testing.T) {
+	input := `let x = 5;
+  x + 10
+`
+
+	tests := []struct {
+		expectedLiteral string
+		expectedLine    int
+		expectedColumn  int
+	}{
+		{"let", 1, 1},
+		{"x", 1, 5},
+		{"=", 1, 7},
+		{"5", 1, 9},
+		{";", 1, 10},
+		{"x", 2, 3},
+		{"+", 2, 5},
+		{"10", 2, 7},
+		{"", 3, 1},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got %q", i, tt.expectedLiteral, tok.Literal)
+		}
+
+		if tok.Line != tt.expectedLine || tok.Column != tt.expectedColumn {
+			t.Fatalf("tests[%d] - position wrong. expected=%d:%d, got %d:%d",
+				i, tt.expectedLine, tt.expectedColumn, tok.Line, tok.Column)
+		}
+	}
+}
+
+func TestComments(t *testing.T) {
+	input := `// leading
+x /= 2 / y; // trailing  
+// last`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.IDENT, "x"},
+		{token.SLASH_ASSIGN, "/="},
+		{token.INT, "2"},
+		{token.SLASH, "/"},
+		{token.IDENT, "y"},
+		{token.SEMICOLON, ";"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - token wrong. expected=%q %q, got %q %q",
+				i, tt.expectedType, tt.expectedLiteral, tok.Type, tok.Literal)
+		}
+	}
+
+	expected := []token.Token{
+		{Type: token.COMMENT, Literal: "// leading", Line: 1, Column: 1},
+		{Type: token.COMMENT, Literal: "// trailing", Line: 2, Column: 13},
+		{Type: token.COMMENT, Literal: "// last", Line: 3, Column: 1},
+	}
+
+	comments := l.Comments()
+	if len(comments) != len(expected) {
+		t.Fatalf("wrong number of comments. expected=%d, got=%d", len(expected), len(comments))
+	}
+
+	for i, comment := range comments {
+		if comment != expected[i] {
+			t.Errorf("comments[%d] wrong. expected=%+v, got=%+v", i, expected[i], comment)
+		}
+	}
+}
+
+func TestTrivia(t *testing.T) {
+	input := "  let x\t= 5; // five\n\n@é\x00\n"
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+		expectedLeading string
+	}{
+		{token.LET, "let", "  "},
+		{token.IDENT, "x", " "},
+		{token.ASSIGN, "=", "\t"},
+		{token.INT, "5", " "},
+		{token.SEMICOLON, ";", ""},
+		{token.ILLEGAL, "@", " // five\n\n"},
+		{token.ILLEGAL, "\xc3", ""},
+		{token.ILLEGAL, "\xa9", ""},
+		{token.ILLEGAL, "\x00", ""},
+		{token.EOF, "", "\n"},
+		{token.EOF, "", ""},
+	}
+
+	l := NewWithTrivia(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral || tok.Leading != tt.expectedLeading {
+			t.Fatalf("tests[%d] - token wrong. expected=%q %q %q, got %q %q %q", i,
+				tt.expectedType, tt.expectedLiteral, tt.expectedLeading, tok.Type, tok.Literal, tok.Leading)
+		}
+	}
+
+	// The comment is still reported
+	if len(l.Comments()) != 1 {
+		t.Fatalf("wrong number of comments. got=%d", len(l.Comments()))
+	}
+}
+
+func TestNewReader(t *testing.T) {
+	inputs := []string{
+		"",
+		"let five = 5;\nlet ten = 10; // ten\n",
+		"x /= 0x1F + 1_000 * 1.5e-3; for (k, v in 0..=10) { h[k] += v; }",
+		"let [a, {b: c = 1}, ...d] = e; @ \x00",
+		strings.Repeat("let abcdefghij = 1234567890; // comment\n", 500),
+	}
+
+	for _, input := range inputs {
+		readers := map[string]io.Reader{
+			"reader":   strings.NewReader(input),
+			"one byte": iotest.OneByteReader(strings.NewReader(input)),
+			"half":     iotest.HalfReader(strings.NewReader(input)),
+		}
+
+		for name, r := range readers {
+			expected := New(input)
+			l := NewReader(r)
+
+			for i := 0; ; i++ {
+				want, got := expected.NextToken(), l.NextToken()
+				if got != want {
+					t.Fatalf("%s: token %d wrong. expected=%+v, got=%+v", name, i, want, got)
+				}
+				if want.Type == token.EOF {
+					break
+				}
+			}
+
+			if l.Err() != nil {
+				t.Fatalf("%s: unexpected error: %s", name, l.Err())
+			}
+		}
+	}
+}
+
+func TestNewReaderBuffer(t *testing.T) {
+	statement := "let x = 1; // one\n"
+	count := 10000
+
+	l := NewReader(strings.NewReader(strings.Repeat(statement, count)))
+
+	tokens := 0
+	for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		tokens++
+
+		if len(l.input) > 2*chunkSize {
+			t.Fatalf("buffer not bounded, holds %d bytes after %d tokens", len(l.input), tokens)
+		}
+	}
+
+	if tokens != 5*count {
+		t.Fatalf("wrong number of tokens. expected=%d, got=%d", 5*count, tokens)
+	}
+	if len(l.Comments()) != 0 {
+		t.Fatalf("comments kept, %d of them", len(l.Comments()))
+	}
+}
+
+func TestNewReaderError(t *testing.T) {
+	errRead := errors.New("read failed")
+	l := NewReader(io.MultiReader(strings.NewReader("let x"), iotest.ErrReader(errRead)))
+
+	for _, expected := range []token.TokenType{token.LET, token.IDENT, token.EOF} {
+		if tok := l.NextToken(); tok.Type != expected {
+			t.Fatalf("token wrong. expected=%q, got=%q", expected, tok.Type)
+		}
+	}
+
+	if l.Err() != errRead {
+		t.Fatalf("wrong error. expected=%v, got=%v", errRead, l.Err())
+	}
+}
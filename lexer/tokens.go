@@ -0,0 +1,41 @@
+package lexer
+
+import (
+	"fmt"
+	"iter"
+
+	"github.com/jeremi-traverse/monkey/token"
+)
+
+// Tokens returns an iterator over the tokens of src, EOF excluded:
+//
+//	for tok := range lexer.Tokens(src) { ... }
+func Tokens(src string) iter.Seq[token.Token] {
+	return func(yield func(token.Token) bool) {
+		l := New(src)
+
+		for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+			if !yield(tok) {
+				return
+			}
+		}
+	}
+}
+
+// Tokenize returns every token of src, EOF excluded, and an error for
+// each ILLEGAL token among them
+func Tokenize(src string) ([]token.Token, []error) {
+	var tokens []token.Token
+	var errors []error
+
+	for tok := range Tokens(src) {
+		tokens = append(tokens, tok)
+
+		if tok.Type == token.ILLEGAL {
+			errors = append(errors, fmt.Errorf("illegal character %q at line %d, column %d",
+				tok.Literal, tok.Line, tok.Column))
+		}
+	}
+
+	return tokens, errors
+}
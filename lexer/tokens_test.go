@@ -0,0 +1,77 @@
+package lexer
+
+import (
+	"testing"
+
+	"github.com/jeremi-traverse/monkey/token"
+)
+
+func TestTokens(t *testing.T) {
+	expected := []token.Token{
+		{Type: token.LET, Literal: "let", Line: 1, Column: 1},
+		{Type: token.IDENT, Literal: "x", Line: 1, Column: 5},
+		{Type: token.ASSIGN, Literal: "=", Line: 1, Column: 7},
+		{Type: token.INT, Literal: "5", Line: 2, Column: 1},
+	}
+
+	i := 0
+	for tok := range Tokens("let x =\n5") {
+		if i >= len(expected) {
+			t.Fatalf("too many tokens. got=%+v", tok)
+		}
+		if tok != expected[i] {
+			t.Fatalf("tokens[%d] wrong. expected=%+v, got=%+v", i, expected[i], tok)
+		}
+		i++
+	}
+
+	if i != len(expected) {
+		t.Fatalf("wrong number of tokens. expected=%d, got=%d", len(expected), i)
+	}
+}
+
+func TestTokensBreak(t *testing.T) {
+	count := 0
+	for range Tokens("a b c d") {
+		count++
+		if count == 2 {
+			break
+		}
+	}
+
+	if count != 2 {
+		t.Fatalf("iteration didn't stop. got=%d tokens", count)
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	tokens, errs := Tokenize("x @ y\n  $")
+
+	expectedTypes := []token.TokenType{token.IDENT, token.ILLEGAL, token.IDENT, token.ILLEGAL}
+	if len(tokens) != len(expectedTypes) {
+		t.Fatalf("wrong number of tokens. expected=%d, got=%d", len(expectedTypes), len(tokens))
+	}
+	for i, tok := range tokens {
+		if tok.Type != expectedTypes[i] {
+			t.Errorf("tokens[%d] wrong type. expected=%q, got=%q", i, expectedTypes[i], tok.Type)
+		}
+	}
+
+	expectedErrors := []string{
+		`illegal character "@" at line 1, column 3`,
+		`illegal character "$" at line 2, column 3`,
+	}
+	if len(errs) != len(expectedErrors) {
+		t.Fatalf("wrong number of errors. expected=%d, got=%v", len(expectedErrors), errs)
+	}
+	for i, err := range errs {
+		if err.Error() != expectedErrors[i] {
+			t.Errorf("errors[%d] wrong. expected=%q, got=%q", i, expectedErrors[i], err)
+		}
+	}
+
+	tokens, errs = Tokenize("")
+	if len(tokens) != 0 || len(errs) != 0 {
+		t.Fatalf("tokens of an empty source. got=%v %v", tokens, errs)
+	}
+}
@@ -7,5 +7,16 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "fmt":
+			os.Exit(runFmt(os.Args[2:], os.Stdin, os.Stdout, os.Stderr))
+		case "ast":
+			os.Exit(runAST(os.Args[2:], os.Stdin, os.Stdout, os.Stderr))
+		case "tokens":
+			os.Exit(runTokens(os.Args[2:], os.Stdin, os.Stdout, os.Stderr))
+		}
+	}
+
 	repl.Start(os.Stdin, os.Stdout)
 }
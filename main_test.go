@@ -0,0 +1,319 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFmtStdin(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	code := runFmt(nil, strings.NewReader("let x=1+2"), &stdout, &stderr)
+
+	if code != 0 || stderr.Len() != 0 {
+		t.Fatalf("wrong exit. code=%d, stderr=%q", code, stderr.String())
+	}
+	if stdout.String() != "let x = 1 + 2;\n" {
+		t.Fatalf("wrong output. got=%q", stdout.String())
+	}
+}
+
+func TestFmtWrite(t *testing.T) {
+	dir := t.TempDir()
+	messy := writeFile(t, dir, "messy.monkey", "let x=1;\nx+=2")
+	clean := writeFile(t, dir, "clean.monkey", "let y = 1;\n")
+
+	var stdout, stderr bytes.Buffer
+	code := runFmt([]string{"-w", messy, clean}, nil, &stdout, &stderr)
+
+	if code != 0 || stderr.Len() != 0 {
+		t.Fatalf("wrong exit. code=%d, stderr=%q", code, stderr.String())
+	}
+	if stdout.Len() != 0 {
+		t.Fatalf("-w printed the files. got=%q", stdout.String())
+	}
+
+	if got := readFile(t, messy); got != "let x = 1;\nx += 2;\n" {
+		t.Errorf("file not formatted. got=%q", got)
+	}
+	if got := readFile(t, clean); got != "let y = 1;\n" {
+		t.Errorf("formatted file changed. got=%q", got)
+	}
+}
+
+func TestFmtDiff(t *testing.T) {
+	file := writeFile(t, t.TempDir(), "a.monkey", "let x=1;\nlet y = 2;\nx")
+
+	var stdout, stderr bytes.Buffer
+	code := runFmt([]string{"-d", file}, nil, &stdout, &stderr)
+
+	if code != 0 || stderr.Len() != 0 {
+		t.Fatalf("wrong exit. code=%d, stderr=%q", code, stderr.String())
+	}
+
+	expected := "--- " + file + ".orig\n+++ " + file + "\n" +
+		"@@ -1,3 +1,3 @@\n" +
+		"-let x=1;\n" +
+		"+let x = 1;\n" +
+		" let y = 2;\n" +
+		"-x\n" +
+		"\\ No newline at end of file\n" +
+		"+x;\n"
+	if stdout.String() != expected {
+		t.Fatalf("wrong diff.\nexpected=%q\ngot=     %q", expected, stdout.String())
+	}
+
+	// -d doesn't write the file
+	if got := readFile(t, file); got != "let x=1;\nlet y = 2;\nx" {
+		t.Fatalf("-d changed the file. got=%q", got)
+	}
+}
+
+func TestFmtErrors(t *testing.T) {
+	dir := t.TempDir()
+	bad := writeFile(t, dir, "bad.monkey", "let = 1;")
+	good := writeFile(t, dir, "good.monkey", "x")
+
+	tests := []struct {
+		args   []string
+		stdin  string
+		stderr string
+	}{
+		{[]string{bad, good}, "", bad + ":\nexpected token type IDENT, got = instead"},
+		{[]string{filepath.Join(dir, "missing.monkey")}, "", "no such file or directory"},
+		{[]string{"-w"}, "x", "cannot use -w with standard input"},
+		{nil, "f(x)", "<standard input>:\nfunction calls are not supported yet"},
+		{[]string{"-unknown"}, "", "usage: monkey fmt"},
+	}
+
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		code := runFmt(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+
+		if code != 2 {
+			t.Errorf("%v: wrong exit code. expected=2, got=%d", tt.args, code)
+		}
+		if !strings.Contains(stderr.String(), tt.stderr) {
+			t.Errorf("%v: wrong stderr. expected=%q, got=%q", tt.args, tt.stderr, stderr.String())
+		}
+	}
+
+	// The files after the bad one are still formatted
+	var stdout, stderr bytes.Buffer
+	runFmt([]string{bad, good}, nil, &stdout, &stderr)
+	if stdout.String() != "x;\n" {
+		t.Errorf("good file not formatted. got=%q", stdout.String())
+	}
+}
+
+func TestASTJSON(t *testing.T) {
+	file := writeFile(t, t.TempDir(), "a.monkey", "let x = 1;")
+
+	var stdout, stderr bytes.Buffer
+	code := runAST([]string{"--json", file}, nil, &stdout, &stderr)
+
+	if code != 0 || stderr.Len() != 0 {
+		t.Fatalf("wrong exit. code=%d, stderr=%q", code, stderr.String())
+	}
+
+	var program struct {
+		Type       string
+		Statements []struct{ Type string }
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &program); err != nil {
+		t.Fatalf("invalid JSON: %s\n%s", err, stdout.String())
+	}
+	if program.Type != "Program" || len(program.Statements) != 1 || program.Statements[0].Type != "LetStatement" {
+		t.Fatalf("wrong tree. got=%+v", program)
+	}
+}
+
+func TestASTDot(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := runAST([]string{"--dot"}, strings.NewReader("a + b"), &stdout, &stderr)
+
+	if code != 0 || stderr.Len() != 0 {
+		t.Fatalf("wrong exit. code=%d, stderr=%q", code, stderr.String())
+	}
+	if !strings.HasPrefix(stdout.String(), "digraph AST {") || !strings.Contains(stdout.String(), `label="InfixExpression\n+"`) {
+		t.Fatalf("wrong graph. got=%s", stdout.String())
+	}
+}
+
+func TestASTErrors(t *testing.T) {
+	tests := []struct {
+		args   []string
+		stdin  string
+		stderr string
+	}{
+		{nil, "x", "usage: monkey ast"},
+		{[]string{"--json", "--dot"}, "x", "usage: monkey ast"},
+		{[]string{"--json", "a", "b"}, "x", "usage: monkey ast"},
+		{[]string{"--json"}, "let = 1;", "<standard input>:\nexpected token type IDENT"},
+		{[]string{"--dot"}, "let x = 1; y = x;", "<standard input>:\ncannot assign to undeclared y at line 1, column 14"},
+		{[]string{"--json", filepath.Join(t.TempDir(), "missing")}, "", "no such file or directory"},
+		{[]string{"--dot", filepath.Join(t.TempDir(), "missing")}, "", "no such file or directory"},
+	}
+
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		code := runAST(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+
+		if code != 2 {
+			t.Errorf("%v: wrong exit code. expected=2, got=%d", tt.args, code)
+		}
+		if !strings.Contains(stderr.String(), tt.stderr) {
+			t.Errorf("%v: wrong stderr. expected=%q, got=%q", tt.args, tt.stderr, stderr.String())
+		}
+		if stdout.Len() != 0 {
+			t.Errorf("%v: unexpected output %q", tt.args, stdout.String())
+		}
+	}
+}
+
+func TestTokens(t *testing.T) {
+	file := writeFile(t, t.TempDir(), "a.monkey", "let x =\n 5;")
+
+	var stdout, stderr bytes.Buffer
+	code := runTokens([]string{file}, nil, &stdout, &stderr)
+
+	if code != 0 || stderr.Len() != 0 {
+		t.Fatalf("wrong exit. code=%d, stderr=%q", code, stderr.String())
+	}
+
+	expected := "1:1\tLET\t\"let\"\n1:5\tIDENT\t\"x\"\n1:7\t=\t\"=\"\n2:2\tINT\t\"5\"\n2:3\t;\t\";\"\n"
+	if stdout.String() != expected {
+		t.Fatalf("wrong output.\nexpected=%q\ngot=     %q", expected, stdout.String())
+	}
+}
+
+func TestTokensErrors(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := runTokens(nil, strings.NewReader("x @"), &stdout, &stderr)
+
+	if code != 1 {
+		t.Fatalf("wrong exit code. expected=1, got=%d", code)
+	}
+	if stderr.String() != "<standard input>: illegal character \"@\" at line 1, column 3\n" {
+		t.Fatalf("wrong stderr. got=%q", stderr.String())
+	}
+	// The tokens are still printed
+	if !strings.Contains(stdout.String(), "1:3\tILLEGAL\t\"@\"") {
+		t.Fatalf("illegal token not printed. got=%q", stdout.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	if code := runTokens([]string{"a", "b"}, nil, &stdout, &stderr); code != 2 {
+		t.Fatalf("wrong exit code for 2 files. expected=2, got=%d", code)
+	}
+}
+
+func TestUnifiedDiffHunks(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n"
+	b := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n15\n16\n"
+
+	expected := "--- f.orig\n+++ f\n" +
+		"@@ -1,6 +1,6 @@\n 1\n 2\n-3\n+three\n 4\n 5\n 6\n" +
+		"@@ -11,5 +11,5 @@\n 11\n 12\n 13\n-14\n 15\n+16\n"
+
+	if got := string(unifiedDiff("f", []byte(a), []byte(b))); got != expected {
+		t.Fatalf("wrong diff.\nexpected=%q\ngot=     %q", expected, got)
+	}
+
+	// Changes closer than twice the context share a hunk
+	b = "1\n2\nthree\n4\n5\n6\n7\neight\n9\n10\n11\n12\n13\n14\n15\n"
+	if got := string(unifiedDiff("f", []byte(a), []byte(b))); strings.Count(got, "@@ -") != 1 {
+		t.Fatalf("expected one hunk. got=%q", got)
+	}
+
+	if got := unifiedDiff("f", []byte(a), []byte(a)); got != nil {
+		t.Fatalf("diff of equal files. got=%q", got)
+	}
+
+	expected = "--- f.orig\n+++ f\n@@ -0,0 +1 @@\n+x;\n"
+	if got := string(unifiedDiff("f", nil, []byte("x;\n"))); got != expected {
+		t.Fatalf("wrong diff from an empty file.\nexpected=%q\ngot=     %q", expected, got)
+	}
+}
+
+// The edit script rebuilds both sides and keeps as many lines as
+// their longest common subsequence
+func TestDiffLinesMinimal(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	randomLines := func() []string {
+		lines := make([]string, r.Intn(10))
+		for i := range lines {
+			lines[i] = string(rune('a' + r.Intn(3)))
+		}
+		return lines
+	}
+
+	for i := 0; i < 2000; i++ {
+		a, b := randomLines(), randomLines()
+
+		var gotA, gotB []string
+		kept := 0
+		for _, line := range diffLines(a, b) {
+			if line.kind != '+' {
+				gotA = append(gotA, line.text)
+			}
+			if line.kind != '-' {
+				gotB = append(gotB, line.text)
+			}
+			if line.kind == ' ' {
+				kept++
+			}
+		}
+
+		if strings.Join(gotA, "") != strings.Join(a, "") || strings.Join(gotB, "") != strings.Join(b, "") {
+			t.Fatalf("edit script of %q into %q doesn't rebuild them", a, b)
+		}
+		if expected := longestCommonSubsequence(a, b); kept != expected {
+			t.Fatalf("edit script of %q into %q not minimal. kept=%d, expected=%d", a, b, kept, expected)
+		}
+	}
+}
+
+func longestCommonSubsequence(a, b []string) int {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	return lcs[0][0]
+}
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
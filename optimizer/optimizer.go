@@ -0,0 +1,273 @@
+package optimizer
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/jeremi-traverse/monkey/ast"
+	"github.com/jeremi-traverse/monkey/token"
+)
+
+// Optimize folds the constant expressions of the program in place
+// i.e. 2 * 3 + 1 -> 7, !true -> false, -(-x) -> x
+//
+// -(-x) -> x applies to names, taken to hold numbers, and to numeric
+// expressions. Other operands are left for the runtime to check:
+// -(-true) is a type error it has to report. The identities x + 0, x - 0,
+// x * 1 and x / 1 only apply to numeric expressions (5 / 0 + 0 -> 5 / 0),
+// not to names whose value could be anything.
+// Monkey has no if expression yet, so there are no branches to prune.
+//
+// Arithmetic mixing an integer and a float promotes the integer to a float,
+// integer with integer stays an integer (7 / 2 -> 3, 7 / 2.0 -> 3.5).
+// Integers that overflow an int64 are promoted to big integers.
+func Optimize(program *ast.Program) *ast.Program {
+	// Post-order so the operands are folded before their expression
+	ast.Apply(program, nil, func(c *ast.Cursor) bool {
+		var folded ast.Expression
+
+		switch node := c.Node().(type) {
+		case *ast.PrefixExpression:
+			folded = foldPrefix(node)
+		case *ast.InfixExpression:
+			folded = foldInfix(node)
+		default:
+			return true
+		}
+
+		if folded != c.Node() {
+			c.Replace(folded)
+		}
+
+		return true
+	})
+
+	return program
+}
+
+func foldPrefix(pe *ast.PrefixExpression) ast.Expression {
+	switch right := pe.Right.(type) {
+	case *ast.IntegerLiteral, *ast.BigIntegerLiteral:
+		if pe.Operator == "-" {
+			value, _ := integerValue(right)
+			return integerLiteral(pe.Token, value.Neg(value))
+		}
+	case *ast.FloatLiteral:
+		if pe.Operator == "-" {
+			return floatLiteral(pe.Token, -right.Value)
+		}
+	case *ast.Boolean:
+		if pe.Operator == "!" {
+			return booleanLiteral(pe.Token, !right.Value)
+		}
+	case *ast.PrefixExpression:
+		// -(-x) -> x, -(-true) is a type error for the runtime to report
+		if pe.Operator == "-" && right.Operator == "-" && (isName(right.Right) || isNumeric(right.Right)) {
+			return right.Right
+		}
+	}
+
+	return pe
+}
+
+func foldInfix(ie *ast.InfixExpression) ast.Expression {
+	left, leftIsInt := integerValue(ie.Left)
+	right, rightIsInt := integerValue(ie.Right)
+
+	if leftIsInt && rightIsInt {
+		return foldIntegerInfix(ie, left, right)
+	}
+
+	leftFloat, leftIsNumber := floatValue(ie.Left)
+	rightFloat, rightIsNumber := floatValue(ie.Right)
+
+	if leftIsNumber && rightIsNumber {
+		return foldFloatInfix(ie, leftFloat, rightFloat)
+	}
+
+	leftBool, leftIsBool := ie.Left.(*ast.Boolean)
+	rightBool, rightIsBool := ie.Right.(*ast.Boolean)
+
+	if leftIsBool && rightIsBool {
+		switch ie.Operator {
+		case "==":
+			return booleanLiteral(ie.Token, leftBool.Value == rightBool.Value)
+		case "!=":
+			return booleanLiteral(ie.Token, leftBool.Value != rightBool.Value)
+		}
+		return ie
+	}
+
+	// Algebraic identities, only when the other operand is known to be
+	// a number: true + 0 is a type error for the runtime to report
+	switch {
+	case rightIsInt && right.Sign() == 0 && (ie.Operator == "+" || ie.Operator == "-") && isNumeric(ie.Left):
+		return ie.Left // x + 0, x - 0
+	case leftIsInt && left.Sign() == 0 && ie.Operator == "+" && isNumeric(ie.Right):
+		return ie.Right // 0 + x
+	case rightIsInt && isOne(right) && (ie.Operator == "*" || ie.Operator == "/") && isNumeric(ie.Left):
+		return ie.Left // x * 1, x / 1
+	case leftIsInt && isOne(left) && ie.Operator == "*" && isNumeric(ie.Right):
+		return ie.Right // 1 * x
+	}
+
+	return ie
+}
+
+// Computed on big integers so overflowing results are promoted
+// instead of wrapping around
+func foldIntegerInfix(ie *ast.InfixExpression, left, right *big.Int) ast.Expression {
+	result := new(big.Int)
+
+	switch ie.Operator {
+	case "+":
+		return integerLiteral(ie.Token, result.Add(left, right))
+	case "-":
+		return integerLiteral(ie.Token, result.Sub(left, right))
+	case "*":
+		return integerLiteral(ie.Token, result.Mul(left, right))
+	case "/":
+		// Division by zero is left for the runtime to report
+		if right.Sign() == 0 {
+			return ie
+		}
+		// Quo truncates toward zero like int64 division
+		return integerLiteral(ie.Token, result.Quo(left, right))
+	case "<":
+		return booleanLiteral(ie.Token, left.Cmp(right) < 0)
+	case ">":
+		return booleanLiteral(ie.Token, left.Cmp(right) > 0)
+	case "==":
+		return booleanLiteral(ie.Token, left.Cmp(right) == 0)
+	case "!=":
+		return booleanLiteral(ie.Token, left.Cmp(right) != 0)
+	}
+
+	return ie
+}
+
+func foldFloatInfix(ie *ast.InfixExpression, left, right float64) ast.Expression {
+	var result float64
+
+	switch ie.Operator {
+	case "+":
+		result = left + right
+	case "-":
+		result = left - right
+	case "*":
+		result = left * right
+	case "/":
+		if right == 0 {
+			return ie
+		}
+		result = left / right
+	case "<":
+		return booleanLiteral(ie.Token, left < right)
+	case ">":
+		return booleanLiteral(ie.Token, left > right)
+	case "==":
+		return booleanLiteral(ie.Token, left == right)
+	case "!=":
+		return booleanLiteral(ie.Token, left != right)
+	default:
+		return ie
+	}
+
+	// Inf can't be written as a literal, leave it to the runtime
+	if math.IsInf(result, 0) {
+		return ie
+	}
+
+	return floatLiteral(ie.Token, result)
+}
+
+// Value of an integer literal, the returned big.Int can be modified
+func integerValue(exp ast.Expression) (*big.Int, bool) {
+	switch exp := exp.(type) {
+	case *ast.IntegerLiteral:
+		return big.NewInt(exp.Value), true
+	case *ast.BigIntegerLiteral:
+		return new(big.Int).Set(exp.Value), true
+	}
+
+	return nil, false
+}
+
+// Whether exp evaluates to a number whatever the variables hold:
+// a numeric literal or arithmetic on them left unfolded (5 / 0)
+func isNumeric(exp ast.Expression) bool {
+	switch exp := exp.(type) {
+	case *ast.IntegerLiteral, *ast.BigIntegerLiteral, *ast.FloatLiteral:
+		return true
+	case *ast.PrefixExpression:
+		return exp.Operator == "-" && isNumeric(exp.Right)
+	case *ast.InfixExpression:
+		switch exp.Operator {
+		case "+", "-", "*", "/":
+			return isNumeric(exp.Left) && isNumeric(exp.Right)
+		}
+	}
+
+	return false
+}
+
+func isName(exp ast.Expression) bool {
+	_, ok := exp.(*ast.Identifier)
+	return ok
+}
+
+func isOne(value *big.Int) bool {
+	return value.IsInt64() && value.Int64() == 1
+}
+
+// Value of a numeric literal promoted to a float
+func floatValue(exp ast.Expression) (float64, bool) {
+	switch exp := exp.(type) {
+	case *ast.IntegerLiteral:
+		return float64(exp.Value), true
+	case *ast.BigIntegerLiteral:
+		value, _ := new(big.Float).SetInt(exp.Value).Float64()
+		return value, true
+	case *ast.FloatLiteral:
+		return exp.Value, true
+	}
+
+	return 0, false
+}
+
+// The folded literal keeps the token of the expression it replaces
+// so it still points to the same place in the source
+// Results that fit in an int64 go back to a plain IntegerLiteral
+func integerLiteral(tok token.Token, value *big.Int) ast.Expression {
+	tok.Type = token.INT
+	tok.Literal = value.String()
+
+	if value.IsInt64() {
+		return &ast.IntegerLiteral{Token: tok, Value: value.Int64()}
+	}
+
+	return &ast.BigIntegerLiteral{Token: tok, Value: value}
+}
+
+func floatLiteral(tok token.Token, value float64) *ast.FloatLiteral {
+	tok.Type = token.FLOAT
+	tok.Literal = strconv.FormatFloat(value, 'g', -1, 64)
+	// Keep it lexing as a float, 7 -> 7.0
+	if !strings.ContainsAny(tok.Literal, ".e") {
+		tok.Literal += ".0"
+	}
+
+	return &ast.FloatLiteral{Token: tok, Value: value}
+}
+
+func booleanLiteral(tok token.Token, value bool) *ast.Boolean {
+	tok.Type = token.FALSE
+	if value {
+		tok.Type = token.TRUE
+	}
+	tok.Literal = strconv.FormatBool(value)
+
+	return &ast.Boolean{Token: tok, Value: value}
+}
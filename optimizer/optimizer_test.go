@@ -0,0 +1,147 @@
+package optimizer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jeremi-traverse/monkey/ast"
+	"github.com/jeremi-traverse/monkey/lexer"
+	"github.com/jeremi-traverse/monkey/parser"
+)
+
+func TestOptimize(t *testing.T) {
+	tests := []struct {
+		input    string
+		before   string
+		expected string
+	}{
+		{"2 * 3 + 1", "((2 * 3) + 1)", "7"},
+		{"1 + 2 * 3 - 4 / 2", "((1 + (2 * 3)) - (4 / 2))", "5"},
+		{"10 / 3", "(10 / 3)", "3"},
+		{"-5 - -5", "((-5) - (-5))", "0"},
+		{"!true", "(!true)", "false"},
+		{"!!false", "(!(!false))", "false"},
+		{"true == false", "(true == false)", "false"},
+		{"1 < 2 == true", "((1 < 2) == true)", "true"},
+		{"3 > 4 != false", "((3 > 4) != false)", "false"},
+		{"--x", "(-(-x))", "x"},
+		{"---x", "(-(-(-x)))", "(-x)"},
+		{"----x", "(-(-(-(-x))))", "x"},
+		{"--x + 1", "((-(-x)) + 1)", "(x + 1)"},
+		{"--(x + 1)", "(-(-(x + 1)))", "(-(-(x + 1)))"},
+		{"--h[0]", "(-(-(h[0])))", "(-(-(h[0])))"},
+		{"x + 0", "(x + 0)", "(x + 0)"},
+		{"x * 1 + 2 * 3", "((x * 1) + (2 * 3))", "((x * 1) + 6)"},
+		{"-(-(5 / 0))", "(-(-(5 / 0)))", "(5 / 0)"},
+		{"5 / 0 + 0", "((5 / 0) + 0)", "(5 / 0)"},
+		{"0 + 5 / 0 - 0", "((0 + (5 / 0)) - 0)", "(5 / 0)"},
+		{"1 * (1.0 / 0) / 1", "((1 * (1.0 / 0)) / 1)", "(1.0 / 0)"},
+		{"true + 0", "(true + 0)", "(true + 0)"},
+		{"0 + false", "(0 + false)", "(0 + false)"},
+		{"true * 1", "(true * 1)", "(true * 1)"},
+		{"1 * true / 1", "((1 * true) / 1)", "((1 * true) / 1)"},
+		{"--true", "(-(-true))", "(-(-true))"},
+		{"x * 0", "(x * 0)", "(x * 0)"},
+		{"5 / 0", "(5 / 0)", "(5 / 0)"},
+		{"-x", "(-x)", "(-x)"},
+		{"!x", "(!x)", "(!x)"},
+		{"1 + true", "(1 + true)", "(1 + true)"},
+		{"1.5 + 2.5", "(1.5 + 2.5)", "4.0"},
+		{"7 / 2", "(7 / 2)", "3"},
+		{"7 / 2.0", "(7 / 2.0)", "3.5"},
+		{"-2.5 * 2", "((-2.5) * 2)", "-5.0"},
+		{"1e-9 * 1e9", "(1e-9 * 1e9)", "1.0"},
+		{"1e300 * 1e300", "(1e300 * 1e300)", "(1e300 * 1e300)"},
+		{"1 < 1.5", "(1 < 1.5)", "true"},
+		{"2.0 == 2", "(2.0 == 2)", "true"},
+		{"1.0 / 0", "(1.0 / 0)", "(1.0 / 0)"},
+		{"9223372036854775807 + 1", "(9223372036854775807 + 1)", "9223372036854775808"},
+		{"-9223372036854775807 - 2", "((-9223372036854775807) - 2)", "-9223372036854775809"},
+		{"4294967296 * 4294967296", "(4294967296 * 4294967296)", "18446744073709551616"},
+		{"18446744073709551616 / 4294967296", "(18446744073709551616 / 4294967296)", "4294967296"},
+		{"9223372036854775808 > 1", "(9223372036854775808 > 1)", "true"},
+		{"9223372036854775808 * 0.5", "(9223372036854775808 * 0.5)", "4.611686018427388e+18"},
+		{"let x = 2 * 3;", "let x = (2 * 3);", "let x = 6;"},
+		{"while (x < 2 + 3) { x + 0; }", "while ((x < (2 + 3))) {(x + 0)}", "while ((x < 5)) {(x + 0)}"},
+		{"for (let i = 1 - 1; i < 2 * 5; i + 1 * 1) {}",
+			"for (let i = (1 - 1); (i < (2 * 5)); (i + (1 * 1))) {}",
+			"for (let i = 0; (i < 10); (i + 1)) {}"},
+		{"for (x in 0..2 * 5) { x * 1; }", "for (x in (0..(2 * 5))) {(x * 1)}", "for (x in (0..10)) {(x * 1)}"},
+		{"arr[1 + 1] = 2 * 2", "((arr[(1 + 1)]) = (2 * 2))", "((arr[2]) = 4)"},
+		{"let [a = 60 * 60, {b: c = 2 - 1}] = x;", "let [a = (60 * 60), {b: c = (2 - 1)}] = x;", "let [a = 3600, {b: c = 1}] = x;"},
+		{"010 == 8", "(010 == 8)", "false"},
+		{"09 + 1", "(09 + 1)", "10"},
+		{"3 + 4; -5 * 5", "(3 + 4)((-5) * 5)", "7-25"},
+	}
+
+	for _, tt := range tests {
+		program := parse(t, tt.input)
+
+		if program.String() != tt.before {
+			t.Fatalf("program.String() before Optimize wrong. expected=%q, got=%q",
+				tt.before, program.String())
+		}
+
+		optimized := Optimize(program)
+		if optimized.String() != tt.expected {
+			t.Errorf("program.String() after Optimize wrong. input=%q expected=%q, got=%q",
+				tt.input, tt.expected, optimized.String())
+		}
+	}
+}
+
+func TestOptimizeKeepsToken(t *testing.T) {
+	program := Optimize(parse(t, "2 * 3"))
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	lit, ok := stmt.Expression.(*ast.IntegerLiteral)
+	if !ok {
+		t.Fatalf("stmt.Expression is not *ast.IntegerLiteral. got=%T", stmt.Expression)
+	}
+
+	if lit.Value != 6 || lit.TokenLiteral() != "6" {
+		t.Fatalf("lit wrong. got Value=%d, TokenLiteral()=%q", lit.Value, lit.TokenLiteral())
+	}
+
+	// Position of the folded * operator
+	if lit.Token.Line != 1 || lit.Token.Column != 3 {
+		t.Fatalf("lit position wrong. expected=1:3, got=%d:%d",
+			lit.Token.Line, lit.Token.Column)
+	}
+}
+
+func TestOptimizeDemotesBigIntegers(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected ast.Expression
+	}{
+		{"9223372036854775807 + 1", &ast.BigIntegerLiteral{}},
+		{"9223372036854775808 - 1", &ast.IntegerLiteral{}},
+		{"-9223372036854775808", &ast.IntegerLiteral{}},
+		{"--9223372036854775808", &ast.BigIntegerLiteral{}},
+	}
+
+	for _, tt := range tests {
+		program := Optimize(parse(t, tt.input))
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		if fmt.Sprintf("%T", stmt.Expression) != fmt.Sprintf("%T", tt.expected) {
+			t.Errorf("wrong literal for %q. expected=%T, got=%T",
+				tt.input, tt.expected, stmt.Expression)
+		}
+	}
+}
+
+func parse(t *testing.T, input string) *ast.Program {
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+
+	for _, msg := range p.Errors() {
+		t.Errorf("parser error: %s", msg)
+	}
+	if len(p.Errors()) != 0 {
+		t.FailNow()
+	}
+
+	return program
+}
@@ -0,0 +1,59 @@
+package parser
+
+import (
+	"reflect"
+
+	"github.com/jeremi-traverse/monkey/ast"
+	"github.com/jeremi-traverse/monkey/cst"
+	"github.com/jeremi-traverse/monkey/token"
+)
+
+// ParseConcrete parses the program like ParseProgram and also returns
+// its concrete syntax tree. The lexer must come from lexer.NewWithTrivia
+// for the tree to hold whitespace and comments.
+// Errors are reported by Errors, the tree still covers every token
+func (p *Parser) ParseConcrete() (*cst.Node, *ast.Program) {
+	// The first token is already the current one
+	p.concrete = []*cst.Node{}
+	p.addConcreteToken(p.currentToken)
+
+	program := p.ParseProgram()
+
+	root := &cst.Node{Kind: "Program", Children: p.concrete}
+	p.concrete = nil
+
+	return root, program
+}
+
+func (p *Parser) addConcreteToken(tok token.Token) {
+	if p.concrete != nil {
+		p.concrete = append(p.concrete, &cst.Node{Token: &tok})
+	}
+}
+
+// Returns where the node starting at the current token begins
+func (p *Parser) startConcrete() int {
+	return len(p.concrete) - 1
+}
+
+// Groups everything from start up to the current token in a node of
+// the type of node
+func (p *Parser) finishConcrete(start int, node ast.Node) {
+	kind := "Bad"
+	if v := reflect.ValueOf(node); v.IsValid() && !v.IsNil() {
+		kind = v.Elem().Type().Name()
+	}
+
+	p.finishConcreteKind(start, kind)
+}
+
+func (p *Parser) finishConcreteKind(start int, kind string) {
+	if p.concrete == nil || start < 0 {
+		return
+	}
+
+	children := make([]*cst.Node, len(p.concrete)-start)
+	copy(children, p.concrete[start:])
+
+	p.concrete = append(p.concrete[:start], &cst.Node{Kind: kind, Children: children})
+}
@@ -0,0 +1,99 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jeremi-traverse/monkey/cst"
+	"github.com/jeremi-traverse/monkey/lexer"
+)
+
+func TestParseConcrete(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let x = -(a + b);",
+			"(Program (LetStatement let (Identifier x) = (PrefixExpression - (GroupedExpression ( (InfixExpression (Identifier a) + (Identifier b)) ))) ;) EOF)"},
+		{"a = b += 1",
+			"(Program (ExpressionStatement (AssignExpression (Identifier a) = (AssignExpression (Identifier b) += (IntegerLiteral 1)))) EOF)"},
+		{"const [a = 1, ...b] = h[0..n];",
+			"(Program (ConstStatement const (ArrayPattern [ (DefaultPattern (Identifier a) = (IntegerLiteral 1)) , ... b ]) = " +
+				"(IndexExpression (Identifier h) [ (RangeExpression (IntegerLiteral 0) .. (Identifier n)) ]) ;) EOF)"},
+		{"let {a, b: c} = x;",
+			"(Program (LetStatement let (HashPattern { (HashPatternEntry a) , (HashPatternEntry b : (Identifier c)) }) = (Identifier x) ;) EOF)"},
+		{"while (x) { break; }",
+			"(Program (WhileStatement while ( (Identifier x) ) (BlockStatement { (BreakStatement break ;) })) EOF)"},
+		{"let = 1;",
+			"(Program (Bad let (Bad =)) (ExpressionStatement (IntegerLiteral 1) ;) EOF)"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.NewWithTrivia(tt.input))
+		root, _ := p.ParseConcrete()
+
+		if got := sexp(root); got != tt.expected {
+			t.Errorf("%q: wrong tree.\nexpected=%s\ngot=     %s", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestParseConcreteRoundTrip(t *testing.T) {
+	inputs := []string{
+		"",
+		"  \n",
+		"let x = 5;",
+		"// header\nlet  x=1 +2 ; // trailing\n\n\twhile(x){x -= 1}\n// footer",
+		"let [a,{b:c=1},...d]=e;for(let i=0;i<10;i+=1){continue;}for(k,v in 0..=n){}",
+		"((a)) * -(b - 1_000) / 0x1F; 1.5e3; 9223372036854775808\r\n",
+		"let = ; } ) @ é \x00 ...",
+	}
+
+	for _, input := range inputs {
+		p := New(lexer.NewWithTrivia(input))
+		root, program := p.ParseConcrete()
+
+		if root.String() != input {
+			t.Errorf("round trip failed.\nexpected=%q\ngot=     %q", input, root.String())
+		}
+
+		// The abstract tree is the same as without trivia
+		p2 := New(lexer.New(input))
+		if program.String() != p2.ParseProgram().String() {
+			t.Errorf("%q: ParseConcrete and ParseProgram disagree", input)
+		}
+	}
+}
+
+func FuzzParseConcrete(f *testing.F) {
+	f.Add("let x = 5; // five\n")
+	f.Add("let [a, {b: c = 1}, ...d] = e;")
+	f.Add("while (x) { for (k, v in 0..=n) { h[k] += (v - 1) * 2; } }")
+	f.Add("const x = 0x1F + 1_000 + 1.5e-3; return;")
+	f.Add("let = ; } ) @ \x00")
+
+	f.Fuzz(func(t *testing.T, input string) {
+		p := New(lexer.NewWithTrivia(input))
+		root, _ := p.ParseConcrete()
+
+		if root.String() != input {
+			t.Fatalf("round trip failed.\nexpected=%q\ngot=     %q", input, root.String())
+		}
+	})
+}
+
+// (Kind child child...) with tokens as their literal
+func sexp(n *cst.Node) string {
+	if n.IsToken() {
+		if n.Token.Literal == "" {
+			return string(n.Token.Type)
+		}
+		return n.Token.Literal
+	}
+
+	parts := []string{n.Kind}
+	for _, child := range n.Children {
+		parts = append(parts, sexp(child))
+	}
+	return "(" + strings.Join(parts, " ") + ")"
+}
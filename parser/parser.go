@@ -1,10 +1,14 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
+	"math/big"
 	"strconv"
+	"strings"
 
 	"github.com/jeremi-traverse/monkey/ast"
+	"github.com/jeremi-traverse/monkey/cst"
 	"github.com/jeremi-traverse/monkey/lexer"
 	"github.com/jeremi-traverse/monkey/token"
 )
@@ -23,43 +27,72 @@ type Parser struct {
 
 	prefixParseFns map[token.TokenType]prefixParseFn
 	infixParseFns  map[token.TokenType]infixParseFn
+
+	// Number of loops around the current token, break and continue need one
+	loopDepth int
+
+	// Names bound in each enclosing block, true for constants.
+	// Names bound outside of what's being parsed (i.e. previous REPL lines)
+	// are unknown here and left for the runtime to check
+	scopes []map[string]bool
+	// Assigning to a name missing from scopes is an error, set by ParseFile
+	checkDeclared bool
+
+	// Nodes of the concrete syntax tree, only built by ParseConcrete
+	concrete []*cst.Node
 }
 
 // Precendes
 const (
 	_int = iota
 	LOWEST
+	ASSIGN      // = or +=
 	EQUALS      // ==
 	LESSGREATER // > or <
+	RANGE       // .. or ..=
 	SUM         // + or -
 	PRODUCT     // *
 	PREFIX      // -X or !X
 	CALL        // myFunction(X)
+	INDEX       // array[index]
 )
 
 var precedence = map[token.TokenType]int{
+	token.ASSIGN:          ASSIGN,
+	token.PLUS_ASSIGN:     ASSIGN,
+	token.MINUS_ASSIGN:    ASSIGN,
+	token.ASTERISK_ASSIGN: ASSIGN,
+	token.SLASH_ASSIGN:    ASSIGN,
+
 	token.EQ:       EQUALS,
 	token.NOT_EQ:   EQUALS,
 	token.LT:       LESSGREATER,
 	token.GT:       LESSGREATER,
+	token.RANGE:    RANGE,
+	token.RANGE_EQ: RANGE,
 	token.PLUS:     SUM,
 	token.MINUS:    SUM,
 	token.ASTERISK: PRODUCT,
 	token.SLASH:    PRODUCT,
+	token.LPAREN:   CALL,
+	token.LBRACKET: INDEX,
 }
 
 func New(l *lexer.Lexer) *Parser {
 	p := &Parser{l: l, errors: []string{}}
+	p.openScope()
 
 	p.prefixParseFns = make(map[token.TokenType]prefixParseFn)
 	p.infixParseFns = make(map[token.TokenType]infixParseFn)
 
 	p.registerPrefix(token.IDENT, p.parseIdentifier)
 	p.registerPrefix(token.INT, p.parseIntegerLiteral)
+	p.registerPrefix(token.FLOAT, p.parseFloatLiteral)
 	p.registerPrefix(token.BANG, p.parsePrefixExpression)
 	p.registerPrefix(token.MINUS, p.parsePrefixExpression)
 	p.registerPrefix(token.TRUE, p.parseBoolean)
 	p.registerPrefix(token.FALSE, p.parseBoolean)
+	p.registerPrefix(token.LPAREN, p.parseGroupedExpression)
 
 	p.registerInfix(token.PLUS, p.parseInfixExpression)
 	p.registerInfix(token.MINUS, p.parseInfixExpression)
@@ -69,6 +102,15 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerInfix(token.NOT_EQ, p.parseInfixExpression)
 	p.registerInfix(token.LT, p.parseInfixExpression)
 	p.registerInfix(token.GT, p.parseInfixExpression)
+	p.registerInfix(token.LPAREN, p.parseCallExpression)
+	p.registerInfix(token.LBRACKET, p.parseIndexExpression)
+	p.registerInfix(token.ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.PLUS_ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.MINUS_ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.ASTERISK_ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.SLASH_ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.RANGE, p.parseRangeExpression)
+	p.registerInfix(token.RANGE_EQ, p.parseRangeExpression)
 
 	// Read two tokens, so currentToken and peekToken are both set
 	p.nextToken()
@@ -90,10 +132,21 @@ func (p *Parser) ParseProgram() *ast.Program {
 		// Get the next statement
 		p.nextToken()
 	}
-	fmt.Println(program.Statements)
+	for _, tok := range p.l.Comments() {
+		program.Comments = append(program.Comments, &ast.Comment{Token: tok})
+	}
+
 	return program
 }
 
+// ParseFile parses the program like ParseProgram, for a source holding
+// the whole program: assigning to a name it doesn't declare is an error.
+// ParseProgram can't tell, the name may come from a previous REPL line
+func (p *Parser) ParseFile() *ast.Program {
+	p.checkDeclared = true
+	return p.ParseProgram()
+}
+
 func (p *Parser) Errors() []string {
 	return p.errors
 }
@@ -101,6 +154,7 @@ func (p *Parser) Errors() []string {
 func (p *Parser) nextToken() {
 	p.currentToken = p.peekToken
 	p.peekToken = p.l.NextToken()
+	p.addConcreteToken(p.currentToken)
 }
 
 // let y = 5;
@@ -109,53 +163,453 @@ func (p *Parser) nextToken() {
 //	Identifier(y)
 //	Expression(5)
 func (p *Parser) parseStatment() ast.Statement {
+	start := p.startConcrete()
+
+	var stmt ast.Statement
 	switch p.currentToken.Type {
 	case token.LET:
-		return p.parseLetStatement()
+		stmt = p.parseLetStatement()
+	case token.CONST:
+		stmt = p.parseConstStatement()
 	case token.RETURN:
-		return p.parseReturnStatement()
+		stmt = p.parseReturnStatement()
+	case token.WHILE:
+		stmt = p.parseWhileStatement()
+	case token.FOR:
+		stmt = p.parseForStatement()
+	case token.BREAK, token.CONTINUE:
+		stmt = p.parseLoopControlStatement()
 	default:
 		// 1 + 2 + 3
-		return p.parseExpressionStatement()
+		stmt = p.parseExpressionStatement()
 	}
+
+	p.finishConcrete(start, stmt)
+	return stmt
 }
 
-func (p *Parser) parseLetStatement() *ast.LetStatement {
+func (p *Parser) parseLetStatement() ast.Statement {
 	stmt := &ast.LetStatement{Token: p.currentToken}
-	if !p.expectPeek(token.IDENT) {
+
+	name, value, ok := p.parseBinding(false)
+	if !ok {
+		return nil
+	}
+	stmt.Name, stmt.Value = name, value
+
+	return stmt
+}
+
+// const x = 5;
+func (p *Parser) parseConstStatement() ast.Statement {
+	stmt := &ast.ConstStatement{Token: p.currentToken}
+
+	name, value, ok := p.parseBinding(true)
+	if !ok {
 		return nil
 	}
+	stmt.Name, stmt.Value = name, value
+
+	return stmt
+}
+
+// The part after let or const: pattern = value;
+// The current token is the let or const
+func (p *Parser) parseBinding(constant bool) (ast.Pattern, ast.Expression, bool) {
+	p.nextToken()
 
-	stmt.Name = &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+	name := p.parsePattern()
+	if name == nil {
+		return nil, nil, false
+	}
 
 	if !p.expectPeek(token.ASSIGN) {
+		return nil, nil, false
+	}
+
+	p.nextToken()
+
+	value := p.parseExpression(LOWEST)
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	p.declarePattern(name, constant)
+
+	return name, value, true
+}
+
+// x, [a, b, ...rest] or {name, age: years}
+func (p *Parser) parsePattern() ast.Pattern {
+	start := p.startConcrete()
+
+	var pattern ast.Pattern
+	switch p.currentToken.Type {
+	case token.IDENT:
+		pattern = &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+	case token.LBRACKET:
+		pattern = p.parseArrayPattern()
+	case token.LBRACE:
+		pattern = p.parseHashPattern()
+	default:
+		p.addPatternError()
+	}
 
+	p.finishConcrete(start, pattern)
+	return pattern
+}
+
+func (p *Parser) addPatternError() {
+	msg := fmt.Sprintf("expected token type %s, got %s instead",
+		token.IDENT, p.currentToken.Type)
+	p.errors = append(p.errors, msg)
+}
+
+// A pattern inside [] or {} can have a default: [a = 1]
+func (p *Parser) parsePatternElement() ast.Pattern {
+	pattern := p.parsePattern()
+	if pattern == nil {
 		return nil
 	}
 
-	// Skipping until we get a semicolon token
-	for !p.currentTokenIs(token.SEMICOLON) {
+	return p.parsePatternDefault(pattern)
+}
+
+func (p *Parser) parsePatternDefault(pattern ast.Pattern) ast.Pattern {
+	if !p.peekTokenIs(token.ASSIGN) {
+		return pattern
+	}
+
+	// The pattern is the last node built
+	start := p.startConcrete()
+
+	p.nextToken()
+	dp := &ast.DefaultPattern{Token: p.currentToken, Target: pattern}
+
+	p.nextToken()
+	// Above ASSIGN so the default doesn't take in an assignment
+	dp.Default = p.parseExpression(ASSIGN)
+
+	p.finishConcrete(start, dp)
+	return dp
+}
+
+// [a, b = 2, ...rest]
+func (p *Parser) parseArrayPattern() ast.Pattern {
+	pattern := &ast.ArrayPattern{Token: p.currentToken, Elements: []ast.Pattern{}}
+
+	if p.peekTokenIs(token.RBRACKET) {
 		p.nextToken()
+		return pattern
 	}
 
-	return stmt
+	for {
+		p.nextToken()
+
+		// The rest is always the last element
+		if p.currentTokenIs(token.ELLIPSIS) {
+			pattern.Rest = p.parseRestIdentifier()
+			if pattern.Rest == nil {
+				return nil
+			}
+			break
+		}
+
+		element := p.parsePatternElement()
+		if element == nil {
+			return nil
+		}
+		pattern.Elements = append(pattern.Elements, element)
+
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.nextToken()
+	}
+
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+
+	return pattern
+}
+
+// {name, age: years = 18, ...rest}
+func (p *Parser) parseHashPattern() ast.Pattern {
+	pattern := &ast.HashPattern{Token: p.currentToken, Entries: []*ast.HashPatternEntry{}}
+
+	if p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+		return pattern
+	}
+
+	for {
+		p.nextToken()
+
+		if p.currentTokenIs(token.ELLIPSIS) {
+			pattern.Rest = p.parseRestIdentifier()
+			if pattern.Rest == nil {
+				return nil
+			}
+			break
+		}
+
+		if !p.currentTokenIs(token.IDENT) {
+			msg := fmt.Sprintf("expected token type %s, got %s instead",
+				token.IDENT, p.currentToken.Type)
+			p.errors = append(p.errors, msg)
+			return nil
+		}
+
+		start := p.startConcrete()
+		entry := &ast.HashPatternEntry{
+			Key: &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal},
+		}
+
+		if p.peekTokenIs(token.COLON) {
+			p.nextToken()
+			p.nextToken()
+			entry.Value = p.parsePatternElement()
+		} else {
+			// {name} binds name, a node of its own so that rewriting
+			// the key doesn't rewrite the binding
+			entry.Value = p.parsePatternDefault(&ast.Identifier{Token: entry.Key.Token, Value: entry.Key.Value})
+		}
+
+		if entry.Value == nil {
+			return nil
+		}
+		p.finishConcrete(start, entry)
+		pattern.Entries = append(pattern.Entries, entry)
+
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.nextToken()
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+
+	return pattern
+}
+
+// ...rest, the current token is the ...
+func (p *Parser) parseRestIdentifier() *ast.Identifier {
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+
+	return &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
 }
 
 func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 	stmt := &ast.ReturnStatement{Token: p.currentToken}
-	/*
-		if !p.expectPeek(token.IDENT) {
+
+	// return; or return at the end of a block has no value
+	if !p.peekTokenIs(token.SEMICOLON) && !p.peekTokenIs(token.RBRACE) && !p.peekTokenIs(token.EOF) {
+		p.nextToken()
+		stmt.ReturnValue = p.parseExpression(LOWEST)
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+// while (x < 10) { ... }
+func (p *Parser) parseWhileStatement() ast.Statement {
+	stmt := &ast.WhileStatement{Token: p.currentToken}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	p.nextToken()
+	stmt.Condition = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	stmt.Body = p.parseLoopBody()
+
+	return stmt
+}
+
+// for (let i = 0; i < 10; i = i + 1) { ... }
+// Each of the three clauses can be left empty: for (;;) { ... }
+func (p *Parser) parseForStatement() ast.Statement {
+	stmt := &ast.ForStatement{Token: p.currentToken}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	p.nextToken()
+
+	// The loop variables only live inside the loop
+	p.openScope()
+	defer p.closeScope()
+
+	// for (x in ...) or for (k, v in ...)
+	if p.currentTokenIs(token.IDENT) && (p.peekTokenIs(token.IN) || p.peekTokenIs(token.COMMA)) {
+		return p.parseForInStatement(stmt.Token)
+	}
+
+	if !p.currentTokenIs(token.SEMICOLON) {
+		// Only a binding or an expression, a block or a return has no
+		// place there
+		switch p.currentToken.Type {
+		case token.RETURN, token.WHILE, token.FOR, token.BREAK, token.CONTINUE:
+			msg := fmt.Sprintf("expected let, const or an expression, got %s at line %d, column %d",
+				p.currentToken.Literal, p.currentToken.Line, p.currentToken.Column)
+			p.errors = append(p.errors, msg)
 			return nil
 		}
-	*/
-	// Skipping until we get a semicolon token
-	for !p.currentTokenIs(token.SEMICOLON) {
+
+		// The init statement consumes its semicolon
+		stmt.Init = p.parseStatment()
+		if stmt.Init == nil {
+			return nil
+		}
+		// It ends on its last token, the one after it should be the semicolon
+		if !p.currentTokenIs(token.SEMICOLON) {
+			p.addPeekError(token.SEMICOLON)
+			return nil
+		}
+	}
+
+	if !p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+		stmt.Condition = p.parseExpression(LOWEST)
+	}
+
+	if !p.expectPeek(token.SEMICOLON) {
+		return nil
+	}
+
+	if !p.peekTokenIs(token.RPAREN) {
 		p.nextToken()
+		stmt.Post = p.parseExpression(LOWEST)
+	}
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
 	}
 
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	stmt.Body = p.parseLoopBody()
+
 	return stmt
 }
 
+// The current token is the first identifier after the (
+func (p *Parser) parseForInStatement(tok token.Token) ast.Statement {
+	stmt := &ast.ForInStatement{Token: tok}
+	stmt.Value = &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+
+	if p.peekTokenIs(token.COMMA) {
+		p.nextToken()
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		stmt.Key = stmt.Value
+		stmt.Value = &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+	}
+
+	if !p.expectPeek(token.IN) {
+		return nil
+	}
+
+	if stmt.Key != nil {
+		p.declare(stmt.Key, false)
+	}
+	p.declare(stmt.Value, false)
+
+	p.nextToken()
+	stmt.Iterable = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	stmt.Body = p.parseLoopBody()
+
+	return stmt
+}
+
+func (p *Parser) parseLoopBody() *ast.BlockStatement {
+	p.loopDepth++
+	defer func() { p.loopDepth-- }()
+
+	return p.parseBlockStatement()
+}
+
+// break; or continue;
+func (p *Parser) parseLoopControlStatement() ast.Statement {
+	tok := p.currentToken
+
+	if p.loopDepth == 0 {
+		msg := fmt.Sprintf("%s outside of a loop at line %d, column %d",
+			tok.Literal, tok.Line, tok.Column)
+		p.errors = append(p.errors, msg)
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	if tok.Type == token.BREAK {
+		return &ast.BreakStatement{Token: tok}
+	}
+
+	return &ast.ContinueStatement{Token: tok}
+}
+
+// The current token is the opening {, stops on the closing }
+func (p *Parser) parseBlockStatement() *ast.BlockStatement {
+	start := p.startConcrete()
+	block := &ast.BlockStatement{Token: p.currentToken}
+	block.Statements = []ast.Statement{}
+
+	p.openScope()
+	defer p.closeScope()
+
+	p.nextToken()
+
+	for !p.currentTokenIs(token.RBRACE) && !p.currentTokenIs(token.EOF) {
+		stmt := p.parseStatment()
+		if stmt != nil {
+			block.Statements = append(block.Statements, stmt)
+		}
+		p.nextToken()
+	}
+
+	if !p.currentTokenIs(token.RBRACE) {
+		p.errors = append(p.errors, "expected token type }, got EOF instead")
+	}
+	block.Rbrace = p.currentToken
+
+	p.finishConcrete(start, block)
+	return block
+}
+
 func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 	stmt := &ast.ExpressionStatement{Token: p.currentToken}
 
@@ -169,6 +623,7 @@ func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 }
 
 func (p *Parser) parseExpression(precedence int) ast.Expression {
+	start := p.startConcrete()
 	prefix := p.prefixParseFns[p.currentToken.Type]
 
 	if prefix == nil {
@@ -176,7 +631,13 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 		return nil
 	}
 
+	// (...) makes its own node
+	grouped := p.currentTokenIs(token.LPAREN)
+
 	exp := prefix()
+	if !grouped {
+		p.finishConcrete(start, exp)
+	}
 
 	for p.peekToken.Type != token.SEMICOLON && precedence < p.peekPrecedence() {
 		infix := p.infixParseFns[p.peekToken.Type]
@@ -187,6 +648,7 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 		p.nextToken()
 
 		exp = infix(exp)
+		p.finishConcrete(start, exp)
 	}
 
 	return exp
@@ -211,15 +673,37 @@ func (p *Parser) parsePrefixExpression() ast.Expression {
 }
 
 func (p *Parser) parseBoolean() ast.Expression {
-	fmt.Println(p.currentToken.Literal)
 	exp := &ast.Boolean{
 		Token: p.currentToken,
-		Value: p.currentTokenIs(p.currentToken.Type),
+		Value: p.currentTokenIs(token.TRUE),
 	}
-	fmt.Println(exp.Token)
 	return exp
 }
 
+// (a + b) * c, the parentheses only change the shape of the tree
+func (p *Parser) parseGroupedExpression() ast.Expression {
+	start := p.startConcrete()
+	p.nextToken()
+
+	exp := p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	p.finishConcreteKind(start, "GroupedExpression")
+	return exp
+}
+
+// f(x), without it the ( would start a new grouped expression
+// and f(x) would silently parse as f; (x);
+func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
+	msg := fmt.Sprintf("function calls are not supported yet at line %d, column %d",
+		p.currentToken.Line, p.currentToken.Column)
+	p.errors = append(p.errors, msg)
+	return nil
+}
+
 func (p *Parser) parseInfixExpression(leftExp ast.Expression) ast.Expression {
 	exp := &ast.InfixExpression{
 		Token:    p.currentToken,
@@ -235,6 +719,141 @@ func (p *Parser) parseInfixExpression(leftExp ast.Expression) ast.Expression {
 	return exp
 }
 
+// arr[i]
+func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
+	exp := &ast.IndexExpression{Token: p.currentToken, Left: left}
+
+	p.nextToken()
+	exp.Index = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+
+	return exp
+}
+
+// x = 5, x += 1, arr[i] = v
+// Right associative: a = b = 1 -> (a = (b = 1))
+func (p *Parser) parseAssignExpression(target ast.Expression) ast.Expression {
+	exp := &ast.AssignExpression{
+		Token:    p.currentToken,
+		Target:   target,
+		Operator: p.currentToken.Literal,
+	}
+
+	// The target failed to parse, its error is already reported
+	if target == nil {
+		return nil
+	}
+
+	switch target.(type) {
+	case *ast.Identifier, *ast.IndexExpression:
+	default:
+		msg := fmt.Sprintf("cannot assign to %s at line %d, column %d",
+			target, exp.Token.Line, exp.Token.Column)
+		p.errors = append(p.errors, msg)
+		return nil
+	}
+
+	if ident, ok := target.(*ast.Identifier); ok {
+		constant, declared := p.lookup(ident.Value)
+		switch {
+		case constant:
+			msg := fmt.Sprintf("cannot assign to constant %s at line %d, column %d",
+				ident.Value, exp.Token.Line, exp.Token.Column)
+			p.errors = append(p.errors, msg)
+		case !declared && p.checkDeclared:
+			msg := fmt.Sprintf("cannot assign to undeclared %s at line %d, column %d",
+				ident.Value, exp.Token.Line, exp.Token.Column)
+			p.errors = append(p.errors, msg)
+		}
+	}
+
+	precedence := p.currTokenPrecedence()
+	p.nextToken()
+
+	// One less than its own precedence so the right side takes in the next assignment
+	exp.Value = p.parseExpression(precedence - 1)
+
+	return exp
+}
+
+// 0..10 or 0..=10
+func (p *Parser) parseRangeExpression(start ast.Expression) ast.Expression {
+	exp := &ast.RangeExpression{
+		Token:     p.currentToken,
+		Start:     start,
+		Inclusive: p.currentTokenIs(token.RANGE_EQ),
+	}
+
+	precedence := p.currTokenPrecedence()
+	p.nextToken()
+
+	exp.End = p.parseExpression(precedence)
+
+	return exp
+}
+
+func (p *Parser) openScope() {
+	p.scopes = append(p.scopes, map[string]bool{})
+}
+
+func (p *Parser) closeScope() {
+	p.scopes = p.scopes[:len(p.scopes)-1]
+}
+
+// Binds name in the innermost scope, a constant can't be declared again
+// in the scope that holds it
+func (p *Parser) declare(name *ast.Identifier, constant bool) {
+	scope := p.scopes[len(p.scopes)-1]
+
+	if scope[name.Value] {
+		msg := fmt.Sprintf("cannot redeclare constant %s at line %d, column %d",
+			name.Value, name.Token.Line, name.Token.Column)
+		p.errors = append(p.errors, msg)
+		return
+	}
+
+	scope[name.Value] = constant
+}
+
+// Binds every identifier of the pattern
+func (p *Parser) declarePattern(pattern ast.Pattern, constant bool) {
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		p.declare(pattern, constant)
+	case *ast.DefaultPattern:
+		p.declarePattern(pattern.Target, constant)
+	case *ast.ArrayPattern:
+		for _, el := range pattern.Elements {
+			p.declarePattern(el, constant)
+		}
+		if pattern.Rest != nil {
+			p.declare(pattern.Rest, constant)
+		}
+	case *ast.HashPattern:
+		for _, entry := range pattern.Entries {
+			p.declarePattern(entry.Value, constant)
+		}
+		if pattern.Rest != nil {
+			p.declare(pattern.Rest, constant)
+		}
+	}
+}
+
+// Whether name is bound in an enclosing scope, and its closest binding
+// a constant
+func (p *Parser) lookup(name string) (constant, declared bool) {
+	for i := len(p.scopes) - 1; i >= 0; i-- {
+		if constant, ok := p.scopes[i][name]; ok {
+			return constant, true
+		}
+	}
+
+	return false, false
+}
+
 func (p *Parser) currentTokenIs(t token.TokenType) bool {
 	return p.currentToken.Type == t
 }
@@ -256,9 +875,15 @@ func (p *Parser) expectPeek(t token.TokenType) bool {
 func (p *Parser) parseIntegerLiteral() ast.Expression {
 	lit := &ast.IntegerLiteral{Token: p.currentToken}
 
-	value, err := strconv.ParseInt(p.currentToken.Literal, 0, 64)
+	digits, base := integerDigits(p.currentToken.Literal)
+
+	value, err := strconv.ParseInt(digits, base, 64)
+	if errors.Is(err, strconv.ErrRange) {
+		return p.parseBigIntegerLiteral()
+	}
 	if err != nil {
-		msg := fmt.Sprintf("could not parse %q as integer", p.currentToken.Literal)
+		msg := fmt.Sprintf("could not parse %q as integer at line %d, column %d",
+			p.currentToken.Literal, p.currentToken.Line, p.currentToken.Column)
 		p.errors = append(p.errors, msg)
 		return nil
 	}
@@ -268,6 +893,68 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 	return lit
 }
 
+// Literals overflowing an int64, i.e. 9223372036854775808
+func (p *Parser) parseBigIntegerLiteral() ast.Expression {
+	lit := &ast.BigIntegerLiteral{Token: p.currentToken}
+
+	digits, base := integerDigits(p.currentToken.Literal)
+
+	value, ok := new(big.Int).SetString(digits, base)
+	if !ok {
+		msg := fmt.Sprintf("could not parse %q as integer at line %d, column %d",
+			p.currentToken.Literal, p.currentToken.Line, p.currentToken.Column)
+		p.errors = append(p.errors, msg)
+		return nil
+	}
+
+	lit.Value = value
+
+	return lit
+}
+
+// Returns the digits of an integer literal and their base.
+// 0x, 0o and 0b literals are left to base 0, it reads the prefix and
+// the _ separators. Anything else is decimal, even with a leading 0
+// (base 0 would read 010 as octal and reject 09)
+func integerDigits(lit string) (string, int) {
+	if len(lit) > 1 && lit[0] == '0' && strings.ContainsRune("xXoObB", rune(lit[1])) {
+		return lit, 0
+	}
+
+	// A _ must be between two digits
+	if strings.HasPrefix(lit, "_") || strings.HasSuffix(lit, "_") || strings.Contains(lit, "__") {
+		// Left as is to be rejected
+		return lit, 10
+	}
+
+	return strings.ReplaceAll(lit, "_", ""), 10
+}
+
+func (p *Parser) parseFloatLiteral() ast.Expression {
+	lit := &ast.FloatLiteral{Token: p.currentToken}
+
+	value, err := strconv.ParseFloat(p.currentToken.Literal, 64)
+	if err != nil {
+		msg := fmt.Sprintf("could not parse %q as float at line %d, column %d",
+			p.currentToken.Literal, p.currentToken.Line, p.currentToken.Column)
+		p.errors = append(p.errors, msg)
+		return nil
+	}
+
+	lit.Value = value
+
+	return lit
+}
+
+// Precedence of the infix operator t, LOWEST when t isn't one
+func Precedence(t token.TokenType) int {
+	if p, ok := precedence[t]; ok {
+		return p
+	}
+
+	return LOWEST
+}
+
 func (p *Parser) currTokenPrecedence() int {
 	if p, ok := precedence[p.currentToken.Type]; ok {
 		return p
@@ -45,6 +45,221 @@ func TestLetStatements(t *testing.T) {
 	}
 }
 
+func TestConstStatement(t *testing.T) {
+	input := `
+		const x = 5;
+		const max = 10 * 2;
+	`
+	l := lexer.New(input)
+	p := New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 2 {
+		t.Fatalf("progam.Statements does not contain 2 statments. got %d", len(program.Statements))
+	}
+
+	tests := []struct {
+		expectedIdentifier string
+		expectedString     string
+	}{
+		{"x", "const x = 5;"},
+		{"max", "const max = (10 * 2);"},
+	}
+
+	for i, tt := range tests {
+		stmt, ok := program.Statements[i].(*ast.ConstStatement)
+		if !ok {
+			t.Fatalf("program.Statements[%d] is not *ast.ConstStatement. got=%T",
+				i, program.Statements[i])
+		}
+
+		if stmt.Name.String() != tt.expectedIdentifier {
+			t.Errorf("stmt.Name not %q. got=%q", tt.expectedIdentifier, stmt.Name)
+		}
+
+		if stmt.String() != tt.expectedString {
+			t.Errorf("stmt.String() wrong. expected=%q, got=%q", tt.expectedString, stmt.String())
+		}
+	}
+}
+
+func TestUndeclaredAssignment(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"x = 1;", []string{"cannot assign to undeclared x at line 1, column 3"}},
+		{"let x = 1; y += x;", []string{"cannot assign to undeclared y at line 1, column 14"}},
+		{"while (c) { let y = 1; } y = 2;", []string{"cannot assign to undeclared y at line 1, column 28"}},
+		{"let x = x = 1;", []string{"cannot assign to undeclared x at line 1, column 11"}},
+		{"let x = 1; x = 2; h[0] = x;", []string{}},
+		{"let [a, {b: c = 1}, ...d] = e; a = c = d;", []string{}},
+		{"for (let i = 0; i < 3; i += 1) { i = 0; }", []string{}},
+		{"for (k, v in h) { k = v; }", []string{}},
+		{"let x = 1; while (c) { x = 2; }", []string{}},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseFile()
+
+		errors := p.Errors()
+		if fmt.Sprint(errors) != fmt.Sprint(tt.expected) {
+			t.Errorf("wrong parser errors for %q. expected=%q, got=%q",
+				tt.input, tt.expected, errors)
+		}
+	}
+
+	// The name may come from elsewhere, i.e. a previous REPL line
+	p := New(lexer.New("x = 1;"))
+	p.ParseProgram()
+	checkParserErrors(t, p)
+}
+
+func TestConstReassignment(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"const x = 1; x = 2;", []string{"cannot assign to constant x at line 1, column 16"}},
+		{"const x = 1; x += 1;", []string{"cannot assign to constant x at line 1, column 16"}},
+		{"const x = 1; let x = 2;", []string{"cannot redeclare constant x at line 1, column 18"}},
+		{"const x = 1; const x = 2;", []string{"cannot redeclare constant x at line 1, column 20"}},
+		{"const x = 1; while (c) { x = 3; }", []string{"cannot assign to constant x at line 1, column 28"}},
+		{"const x = 1; while (c) { let x = 2; x = 3; }", []string{}},
+		{"const x = 1; for (x in arr) { x = 2; }", []string{}},
+		{"const x = 1; for (let x = 0; x < 10; x += 1) {}", []string{}},
+		{"while (c) { const x = 1; } x = 2;", []string{}},
+		{"let x = 1; x = 2; const y = arr; y[0] = 2;", []string{}},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if fmt.Sprint(errors) != fmt.Sprint(tt.expected) {
+			t.Errorf("wrong parser errors for %q. expected=%q, got=%q",
+				tt.input, tt.expected, errors)
+		}
+	}
+}
+
+func TestDestructuringLetStatement(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let [a, b] = arr;", "let [a, b] = arr;"},
+		{"let [a, b, ...rest] = arr;", "let [a, b, ...rest] = arr;"},
+		{"let [...all] = arr;", "let [...all] = arr;"},
+		{"let [] = arr;", "let [] = arr;"},
+		{"let [a = 1, [b, c = a + 1]] = arr;", "let [a = 1, [b, c = (a + 1)]] = arr;"},
+		{"let {name, age: years} = person;", "let {name, age: years} = person;"},
+		{"let {name = n, age: years = 18, ...rest} = person;", "let {name = n, age: years = 18, ...rest} = person;"},
+		{"let {address: {city}, tags: [first]} = person;", "let {address: {city}, tags: [first]} = person;"},
+		{"const {host, port = 8080} = config;", "const {host, port = 8080} = config;"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements does not contain 1 statement. got=%d",
+				len(program.Statements))
+		}
+
+		if program.String() != tt.expected {
+			t.Errorf("program.String() wrong. expected=%q, got=%q",
+				tt.expected, program.String())
+		}
+	}
+
+	program := New(lexer.New("let [a, {b: c = 2}, ...d] = x;")).ParseProgram()
+	stmt := program.Statements[0].(*ast.LetStatement)
+
+	pattern, ok := stmt.Name.(*ast.ArrayPattern)
+	if !ok {
+		t.Fatalf("stmt.Name is not *ast.ArrayPattern. got=%T", stmt.Name)
+	}
+
+	if len(pattern.Elements) != 2 {
+		t.Fatalf("pattern.Elements does not contain 2 elements. got=%d", len(pattern.Elements))
+	}
+
+	testIdentifier(t, pattern.Elements[0].(*ast.Identifier), "a")
+	testIdentifier(t, pattern.Rest, "d")
+
+	hash, ok := pattern.Elements[1].(*ast.HashPattern)
+	if !ok {
+		t.Fatalf("pattern.Elements[1] is not *ast.HashPattern. got=%T", pattern.Elements[1])
+	}
+
+	testIdentifier(t, hash.Entries[0].Key, "b")
+
+	dp, ok := hash.Entries[0].Value.(*ast.DefaultPattern)
+	if !ok {
+		t.Fatalf("hash.Entries[0].Value is not *ast.DefaultPattern. got=%T", hash.Entries[0].Value)
+	}
+
+	testIdentifier(t, dp.Target.(*ast.Identifier), "c")
+	testLiteralExpression(t, dp.Default, 2)
+}
+
+func TestHashPatternShorthand(t *testing.T) {
+	program := New(lexer.New("let {a, b = 1} = x;")).ParseProgram()
+	hash := program.Statements[0].(*ast.LetStatement).Name.(*ast.HashPattern)
+
+	value, ok := hash.Entries[0].Value.(*ast.Identifier)
+	if !ok {
+		t.Fatalf("hash.Entries[0].Value is not *ast.Identifier. got=%T", hash.Entries[0].Value)
+	}
+	testIdentifier(t, value, "a")
+
+	if value == hash.Entries[0].Key {
+		t.Fatalf("the key and the value of {a} are the same node")
+	}
+
+	dp := hash.Entries[1].Value.(*ast.DefaultPattern)
+	if dp.Target == hash.Entries[1].Key {
+		t.Fatalf("the key and the target of {b = 1} are the same node")
+	}
+}
+
+func TestDestructuringErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let 5 = x;", "expected token type IDENT, got INT instead"},
+		{"let [a, ...rest, b] = x;", "expected token type ], got , instead"},
+		{"let {1: a} = x;", "expected token type IDENT, got INT instead"},
+		{"let [a b] = x;", "expected token type ], got IDENT instead"},
+		{"const [a, ...b] = x; b = 1;", "cannot assign to constant b at line 1, column 24"},
+		{"const {a: {b}} = x; a = 1; b = 2;", "cannot assign to constant b at line 1, column 30"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("wrong parser errors for %q. expected=%q, got=%q",
+				tt.input, tt.expected, errors)
+		}
+	}
+}
+
 func TestReturnStatement(t *testing.T) {
 	input := `
 		return 10;
@@ -79,6 +294,49 @@ func TestReturnStatement(t *testing.T) {
 	}
 }
 
+func TestReturnStatementValue(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"return 5;", "5"},
+		{"return x + y", "(x + y)"},
+		{"return;", ""},
+		{"return", ""},
+		{"while (x) { return }", ""},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		statements := program.Statements
+		if loop, ok := statements[0].(*ast.WhileStatement); ok {
+			statements = loop.Body.Statements
+		}
+
+		if len(statements) != 1 {
+			t.Fatalf("%q: expected 1 statement. got=%d", tt.input, len(statements))
+		}
+
+		stmt, ok := statements[0].(*ast.ReturnStatement)
+		if !ok {
+			t.Fatalf("%q: expected Return Statement got %T", tt.input, statements[0])
+		}
+
+		value := ""
+		if stmt.ReturnValue != nil {
+			value = stmt.ReturnValue.String()
+		}
+
+		if value != tt.expected {
+			t.Errorf("%q: wrong return value. expected=%q, got=%q", tt.input, tt.expected, value)
+		}
+	}
+}
+
 func checkParserErrors(t *testing.T, p *Parser) {
 	errors := p.Errors()
 	if len(errors) == 0 {
@@ -105,20 +363,276 @@ func testLetStatement(t *testing.T, s ast.Statement, expectedName string) bool {
 		return false
 	}
 
-	if letStmt.Name.Value != expectedName {
-		t.Errorf("letStmt.Name not %q. got=%q", expectedName, letStmt.Name)
+	name, ok := letStmt.Name.(*ast.Identifier)
+	if !ok {
+		t.Errorf("letStmt.Name isn't an Identifier. got=%T", letStmt.Name)
 		return false
 	}
 
-	if letStmt.Name.TokenLiteral() != expectedName {
+	if name.Value != expectedName {
+		t.Errorf("letStmt.Name not %q. got=%q", expectedName, name)
+		return false
+	}
+
+	if name.TokenLiteral() != expectedName {
 		t.Errorf("letStmt.Name.TokenLiteral() not %q. got=%q",
 			expectedName,
-			letStmt.Name.TokenLiteral())
+			name.TokenLiteral())
 	}
 
 	return true
 }
 
+func TestWhileStatement(t *testing.T) {
+	input := `while (x < 10) { x; break; continue; }`
+
+	l := lexer.New(input)
+	p := New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1 statement. got=%d",
+			len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.WhileStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not *ast.WhileStatement. got=%T",
+			program.Statements[0])
+	}
+
+	if !testInfixExpression(t, stmt.Condition, "<", "x", 10) {
+		return
+	}
+
+	if len(stmt.Body.Statements) != 3 {
+		t.Fatalf("stmt.Body.Statements does not contain 3 statements. got=%d",
+			len(stmt.Body.Statements))
+	}
+
+	if _, ok := stmt.Body.Statements[1].(*ast.BreakStatement); !ok {
+		t.Fatalf("stmt.Body.Statements[1] is not *ast.BreakStatement. got=%T",
+			stmt.Body.Statements[1])
+	}
+
+	if _, ok := stmt.Body.Statements[2].(*ast.ContinueStatement); !ok {
+		t.Fatalf("stmt.Body.Statements[2] is not *ast.ContinueStatement. got=%T",
+			stmt.Body.Statements[2])
+	}
+}
+
+func TestForStatement(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"for (let i = 0; i < 10; i) { i; }", "for (let i = 0; (i < 10); i) {i}"},
+		{"for (i; i < 10;) { break; }", "for (i; (i < 10); ) {break;}"},
+		{"for (;;) {}", "for (; ; ) {}"},
+		{"for (;;) { for (;;) { continue; } break; }", "for (; ; ) {for (; ; ) {continue;}break;}"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements does not contain 1 statement. got=%d",
+				len(program.Statements))
+		}
+
+		if _, ok := program.Statements[0].(*ast.ForStatement); !ok {
+			t.Fatalf("program.Statements[0] is not *ast.ForStatement. got=%T",
+				program.Statements[0])
+		}
+
+		if program.String() != tt.expected {
+			t.Errorf("program.String() wrong. expected=%q, got=%q",
+				tt.expected, program.String())
+		}
+	}
+}
+
+func TestForInStatement(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedKey   string
+		expectedValue string
+		expected      string
+	}{
+		{"for (x in arr) { x; }", "", "x", "for (x in arr) {x}"},
+		{"for (k, v in hash) { k; v; }", "k", "v", "for (k, v in hash) {kv}"},
+		{"for (i in 0..n + 1) { break; }", "", "i", "for (i in (0..(n + 1))) {break;}"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt, ok := program.Statements[0].(*ast.ForInStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not *ast.ForInStatement. got=%T",
+				program.Statements[0])
+		}
+
+		if tt.expectedKey == "" && stmt.Key != nil {
+			t.Errorf("stmt.Key is not nil. got=%s", stmt.Key)
+		}
+
+		if tt.expectedKey != "" {
+			testIdentifier(t, stmt.Key, tt.expectedKey)
+		}
+
+		testIdentifier(t, stmt.Value, tt.expectedValue)
+
+		if program.String() != tt.expected {
+			t.Errorf("program.String() wrong. expected=%q, got=%q",
+				tt.expected, program.String())
+		}
+	}
+}
+
+func TestRangeExpression(t *testing.T) {
+	tests := []struct {
+		input     string
+		start     interface{}
+		end       interface{}
+		inclusive bool
+	}{
+		{"0..10", 0, 10, false},
+		{"a..=b", "a", "b", true},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		exp, ok := stmt.Expression.(*ast.RangeExpression)
+		if !ok {
+			t.Fatalf("stmt.Expression is not *ast.RangeExpression. got=%T",
+				stmt.Expression)
+		}
+
+		testLiteralExpression(t, exp.Start, tt.start)
+		testLiteralExpression(t, exp.End, tt.end)
+
+		if exp.Inclusive != tt.inclusive {
+			t.Errorf("exp.Inclusive is not %t. got=%t", tt.inclusive, exp.Inclusive)
+		}
+	}
+}
+
+func TestAssignExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"x = 5;", "(x = 5)"},
+		{"x += 1;", "(x += 1)"},
+		{"x -= y * 2;", "(x -= (y * 2))"},
+		{"x *= 2; x /= 2;", "(x *= 2)(x /= 2)"},
+		{"a = b = 1;", "(a = (b = 1))"},
+		{"arr[i] = v;", "((arr[i]) = v)"},
+		{"h[k] += a[i + 1];", "((h[k]) += (a[(i + 1)]))"},
+		{"x = a == b;", "(x = (a == b))"},
+		{"for (let i = 0; i < 10; i = i + 1) {}", "for (let i = 0; (i < 10); (i = (i + 1))) {}"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("program.String() wrong. expected=%q, got=%q",
+				tt.expected, program.String())
+		}
+	}
+
+	program := New(lexer.New("x += 1;")).ParseProgram()
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	exp, ok := stmt.Expression.(*ast.AssignExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not *ast.AssignExpression. got=%T", stmt.Expression)
+	}
+
+	testIdentifier(t, exp.Target, "x")
+	if exp.Operator != "+=" {
+		t.Errorf("exp.Operator is not +=. got=%s", exp.Operator)
+	}
+	testLiteralExpression(t, exp.Value, 1)
+}
+
+func TestInvalidAssignTarget(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1 = 2;", "cannot assign to 1 at line 1, column 3"},
+		{"a + b = 2;", "cannot assign to (a + b) at line 1, column 7"},
+		{"-x += 1;", "cannot assign to (-x) at line 1, column 4"},
+		// Only the error of the target
+		{"0x = 5;", `could not parse "0x" as integer at line 1, column 1`},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) != 1 || errors[0] != tt.expected {
+			t.Errorf("wrong parser errors for %q. expected=%q, got=%q",
+				tt.input, tt.expected, errors)
+		}
+	}
+}
+
+func TestLoopErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"break;", "break outside of a loop at line 1, column 1"},
+		{"while (x) {}\ncontinue;", "continue outside of a loop at line 2, column 1"},
+		{"while (x) { x", "expected token type }, got EOF instead"},
+		{"for (let i = 0 i) {}", "expected token type ;, got IDENT instead"},
+		{"for (x y; ;) {}", "expected token type ;, got IDENT instead"},
+		{"for (return 1; ;) {}", "expected let, const or an expression, got return at line 1, column 6"},
+		{"for (while (x) {}; ;) {}", "expected let, const or an expression, got while at line 1, column 6"},
+		{"for (for (;;) {}; ;) {}", "expected let, const or an expression, got for at line 1, column 6"},
+		{"while (x) { for (break; ;) {} }", "expected let, const or an expression, got break at line 1, column 18"},
+		{"for (k, 1 in h) {}", "expected token type IDENT, got INT instead"},
+		{"for (x in arr { x }", "expected token type ), got { instead"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("wrong parser errors for %q. expected=%q, got=%q",
+				tt.input, tt.expected, errors)
+		}
+	}
+}
+
 func TestIdentifierExpression(t *testing.T) {
 	input := "foobar"
 
@@ -196,6 +710,165 @@ func TestIntegerLiteralExpression(t *testing.T) {
 
 }
 
+func TestFloatLiteralExpression(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected float64
+	}{
+		{"3.14;", 3.14},
+		{"1e-9;", 1e-9},
+		{"2.5E+3;", 2500},
+	}
+
+	for _, tc := range testCases {
+		l := lexer.New(tc.input)
+		p := New(l)
+
+		program := p.ParseProgram()
+
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program has not enough statements. got=%d",
+				len(program.Statements))
+		}
+
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not an ExpressionStatement. got=%T",
+				program.Statements[0])
+		}
+
+		lit, ok := stmt.Expression.(*ast.FloatLiteral)
+		if !ok {
+			t.Fatalf("statement is not an *ast.FloatLiteral. got=%T",
+				stmt.Expression)
+		}
+
+		if lit.Value != tc.expected {
+			t.Fatalf("lit.Value is not %g. got=%g", tc.expected, lit.Value)
+		}
+	}
+}
+
+func TestPrefixedIntegerLiteral(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected int64
+	}{
+		{"0xFF;", 255},
+		{"0o17;", 15},
+		{"0b1010;", 10},
+		{"1_000_000;", 1000000},
+		{"0x_1F;", 31},
+		// A leading zero isn't octal, 0o is
+		{"09;", 9},
+		{"010;", 10},
+		{"0_7;", 7},
+	}
+
+	for _, tc := range testCases {
+		l := lexer.New(tc.input)
+		p := New(l)
+
+		program := p.ParseProgram()
+
+		checkParserErrors(t, p)
+
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not an ExpressionStatement. got=%T",
+				program.Statements[0])
+		}
+
+		lit, ok := stmt.Expression.(*ast.IntegerLiteral)
+		if !ok {
+			t.Fatalf("statement is not an *ast.IntegerLiteral. got=%T",
+				stmt.Expression)
+		}
+
+		if lit.Value != tc.expected {
+			t.Fatalf("lit.Value is not %d. got=%d", tc.expected, lit.Value)
+		}
+
+		if lit.TokenLiteral() != tc.input[:len(tc.input)-1] {
+			t.Fatalf("lit.TokenLiteral() is not %s. got=%s",
+				tc.input[:len(tc.input)-1], lit.TokenLiteral())
+		}
+	}
+}
+
+func TestBigIntegerLiteral(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"9223372036854775808;", "9223372036854775808"},
+		{"100_000_000_000_000_000_000;", "100000000000000000000"},
+		{"0x1_0000_0000_0000_0000;", "18446744073709551616"},
+	}
+
+	for _, tc := range testCases {
+		l := lexer.New(tc.input)
+		p := New(l)
+
+		program := p.ParseProgram()
+
+		checkParserErrors(t, p)
+
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not an ExpressionStatement. got=%T",
+				program.Statements[0])
+		}
+
+		lit, ok := stmt.Expression.(*ast.BigIntegerLiteral)
+		if !ok {
+			t.Fatalf("statement is not an *ast.BigIntegerLiteral. got=%T",
+				stmt.Expression)
+		}
+
+		if lit.Value.String() != tc.expected {
+			t.Fatalf("lit.Value is not %s. got=%s", tc.expected, lit.Value)
+		}
+	}
+
+	// The largest int64 is still a plain integer
+	program := New(lexer.New("9223372036854775807")).ParseProgram()
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	testInteger(t, 9223372036854775807, stmt.Expression)
+}
+
+func TestMalformedNumberLiteral(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"1e;", `could not parse "1e" as float at line 1, column 1`},
+		{"let x = 1;\n  5 + 0x;", `could not parse "0x" as integer at line 2, column 7`},
+		{"1__0;", `could not parse "1__0" as integer at line 1, column 1`},
+		{"1_;", `could not parse "1_" as integer at line 1, column 1`},
+		{"0b102;", `could not parse "0b102" as integer at line 1, column 1`},
+		{"0__7;", `could not parse "0__7" as integer at line 1, column 1`},
+		{"099999999999999999999_;", `could not parse "099999999999999999999_" as integer at line 1, column 1`},
+	}
+
+	for _, tc := range testCases {
+		l := lexer.New(tc.input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) != 1 {
+			t.Fatalf("expected 1 parser error for %q. got=%v", tc.input, errors)
+		}
+
+		if errors[0] != tc.expected {
+			t.Fatalf("wrong error message. expected=%q, got=%q", tc.expected, errors[0])
+		}
+	}
+}
+
 func TestBooleanExpression(t *testing.T) {
 	testCases := []struct {
 		input    string
@@ -311,6 +984,53 @@ func TestInfixExpression(t *testing.T) {
 	}
 }
 
+func TestCallExpressionError(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"f(x);", "function calls are not supported yet at line 1, column 2"},
+		{"let y = 1 + f (x);", "function calls are not supported yet at line 1, column 15"},
+		{"(a)(b);", "function calls are not supported yet at line 1, column 4"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("wrong parser errors for %q. expected=%q, got=%q",
+				tt.input, tt.expected, errors)
+		}
+	}
+}
+
+func TestGroupedExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"(a + b) * c", "((a + b) * c)"},
+		{"a * (b + c)", "(a * (b + c))"},
+		{"-(a + b)", "(-(a + b))"},
+		{"((a))", "a"},
+		{"(a = b) + c", "((a = b) + c)"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("program.String() wrong. expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
 func TextComplexInfixExpression(t *testing.T) {
 	infixTests := []struct {
 		input    string
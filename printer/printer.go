@@ -0,0 +1,446 @@
+package printer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+
+	"github.com/jeremi-traverse/monkey/ast"
+	"github.com/jeremi-traverse/monkey/lexer"
+	"github.com/jeremi-traverse/monkey/parser"
+	"github.com/jeremi-traverse/monkey/token"
+)
+
+// Format parses src and returns it in the canonical Monkey style
+func Format(src []byte) ([]byte, error) {
+	p := parser.New(lexer.New(string(src)))
+	program := p.ParseProgram()
+
+	if len(p.Errors()) != 0 {
+		return nil, errors.New(strings.Join(p.Errors(), "\n"))
+	}
+
+	var out bytes.Buffer
+	if err := Fprint(&out, program); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+// Fprint writes node as Monkey source to w:
+// one statement per line, blocks indented with tabs, single spaces
+// around binary operators and only the parentheses the grouping needs.
+// The comments of a *ast.Program are printed back between its statements
+func Fprint(w io.Writer, node ast.Node) error {
+	p := &printer{}
+
+	switch node := node.(type) {
+	case *ast.Program:
+		p.comments = node.Comments
+		p.statements(node.Statements, token.Token{Line: math.MaxInt})
+	case ast.Statement:
+		p.statement(node)
+	case ast.Expression:
+		p.expression(node, parser.LOWEST)
+	case ast.Pattern:
+		p.pattern(node)
+	default:
+		return fmt.Errorf("printer: unexpected node type %T", node)
+	}
+
+	_, err := w.Write(p.out.Bytes())
+	return err
+}
+
+type printer struct {
+	out    bytes.Buffer
+	indent int
+
+	// Comments not printed yet, in source order
+	comments []*ast.Comment
+	// Source line of the last statement or comment printed in the
+	// current block, 0 at the start of a block
+	lastLine int
+}
+
+func (p *printer) write(s string) {
+	p.out.WriteString(s)
+}
+
+func (p *printer) writeIndent() {
+	p.write(strings.Repeat("\t", p.indent))
+}
+
+// Keeps one empty line where the source had one or more
+func (p *printer) separate(line int) {
+	if p.lastLine > 0 && line > p.lastLine+1 {
+		p.write("\n")
+	}
+}
+
+// Prints the comments above line on their own lines
+func (p *printer) flushComments(line int) {
+	for len(p.comments) > 0 && p.comments[0].Token.Line < line {
+		comment := p.comments[0]
+		p.comments = p.comments[1:]
+
+		p.separate(comment.Token.Line)
+		p.writeIndent()
+		p.write(comment.Token.Literal + "\n")
+		p.lastLine = comment.Token.Line
+	}
+}
+
+// Takes the comments up to the end line of stmt that aren't in one of
+// its blocks: the comment after it and those between its tokens,
+// i.e. let x = 1 + // one
+// The comments from next on (the next statement or the closing brace)
+// aren't stmt's, even on the same line: x; y; // y
+func (p *printer) takeInnerComments(stmt ast.Statement, end int, next token.Token) []*ast.Comment {
+	var blocks []*ast.BlockStatement
+	ast.Inspect(stmt, func(n ast.Node) bool {
+		if block, ok := n.(*ast.BlockStatement); ok {
+			blocks = append(blocks, block)
+		}
+		return true
+	})
+
+	var inner, rest []*ast.Comment
+	i := 0
+	for ; i < len(p.comments) && p.comments[i].Token.Line <= end && before(p.comments[i].Token, next); i++ {
+		comment := p.comments[i]
+		if inBlock(comment.Token, blocks) {
+			rest = append(rest, comment)
+		} else {
+			inner = append(inner, comment)
+		}
+	}
+
+	p.comments = append(rest, p.comments[i:]...)
+	return inner
+}
+
+// Prints the first comment at the end of the line of the statement,
+// the others can't fit there and go on their own lines below
+func (p *printer) innerComments(comments []*ast.Comment) {
+	for i, comment := range comments {
+		if i == 0 {
+			p.write(" " + comment.Token.Literal)
+			continue
+		}
+
+		p.write("\n")
+		p.writeIndent()
+		p.write(comment.Token.Literal)
+	}
+}
+
+func inBlock(tok token.Token, blocks []*ast.BlockStatement) bool {
+	for _, block := range blocks {
+		if before(block.Token, tok) && before(tok, block.Rbrace) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func before(a, b token.Token) bool {
+	return a.Line < b.Line || a.Line == b.Line && a.Column < b.Column
+}
+
+// Prints statements one per line, the comments before end
+// (the closing brace of the block) are printed with them
+func (p *printer) statements(statements []ast.Statement, end token.Token) {
+	for i, stmt := range statements {
+		start := tokenOf(stmt).Line
+
+		p.flushComments(start)
+		p.separate(start)
+
+		last := lastLine(stmt)
+		next := end
+		if i+1 < len(statements) {
+			next = tokenOf(statements[i+1])
+		}
+		// Taken before the blocks of stmt print the comments above them
+		inner := p.takeInnerComments(stmt, last, next)
+
+		p.writeIndent()
+		p.statement(stmt)
+
+		p.lastLine = last
+		p.innerComments(inner)
+		p.write("\n")
+	}
+
+	p.flushComments(end.Line)
+}
+
+func (p *printer) statement(stmt ast.Statement) {
+	switch stmt := stmt.(type) {
+	case *ast.LetStatement, *ast.ConstStatement, *ast.ExpressionStatement:
+		p.simpleStatement(stmt)
+		p.write(";")
+	case *ast.ReturnStatement:
+		p.write("return")
+		if stmt.ReturnValue != nil {
+			p.write(" ")
+			p.expression(stmt.ReturnValue, parser.LOWEST)
+		}
+		p.write(";")
+	case *ast.BreakStatement:
+		p.write("break;")
+	case *ast.ContinueStatement:
+		p.write("continue;")
+	case *ast.BlockStatement:
+		p.block(stmt)
+	case *ast.WhileStatement:
+		p.write("while (")
+		p.expression(stmt.Condition, parser.LOWEST)
+		p.write(") ")
+		p.block(stmt.Body)
+	case *ast.ForStatement:
+		p.write("for (")
+		if stmt.Init != nil {
+			p.simpleStatement(stmt.Init)
+		}
+		p.write(";")
+		if stmt.Condition != nil {
+			p.write(" ")
+			p.expression(stmt.Condition, parser.LOWEST)
+		}
+		p.write(";")
+		if stmt.Post != nil {
+			p.write(" ")
+			p.expression(stmt.Post, parser.LOWEST)
+		}
+		p.write(") ")
+		p.block(stmt.Body)
+	case *ast.ForInStatement:
+		p.write("for (")
+		if stmt.Key != nil {
+			p.write(stmt.Key.Value + ", ")
+		}
+		p.write(stmt.Value.Value + " in ")
+		p.expression(stmt.Iterable, parser.LOWEST)
+		p.write(") ")
+		p.block(stmt.Body)
+	default:
+		panic(fmt.Sprintf("printer: unexpected statement type %T", stmt))
+	}
+}
+
+// A statement without its semicolon, as in a for init clause
+func (p *printer) simpleStatement(stmt ast.Statement) {
+	switch stmt := stmt.(type) {
+	case *ast.LetStatement:
+		p.write("let ")
+		p.pattern(stmt.Name)
+		p.write(" = ")
+		p.expression(stmt.Value, parser.LOWEST)
+	case *ast.ConstStatement:
+		p.write("const ")
+		p.pattern(stmt.Name)
+		p.write(" = ")
+		p.expression(stmt.Value, parser.LOWEST)
+	case *ast.ExpressionStatement:
+		p.expression(stmt.Expression, parser.LOWEST)
+	default:
+		p.statement(stmt)
+	}
+}
+
+func (p *printer) block(block *ast.BlockStatement) {
+	hasComments := len(p.comments) > 0 && p.comments[0].Token.Line < block.Rbrace.Line
+	if len(block.Statements) == 0 && !hasComments {
+		p.write("{}")
+		return
+	}
+
+	p.write("{\n")
+	p.indent++
+	p.lastLine = 0
+
+	p.statements(block.Statements, block.Rbrace)
+
+	p.indent--
+	p.writeIndent()
+	p.write("}")
+}
+
+func (p *printer) pattern(pattern ast.Pattern) {
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		p.write(pattern.Value)
+	case *ast.DefaultPattern:
+		p.pattern(pattern.Target)
+		p.write(" = ")
+		// The default is parsed above assignments
+		p.expression(pattern.Default, parser.ASSIGN+1)
+	case *ast.ArrayPattern:
+		p.write("[")
+		for i, el := range pattern.Elements {
+			if i > 0 {
+				p.write(", ")
+			}
+			p.pattern(el)
+		}
+		p.rest(pattern.Rest, len(pattern.Elements) > 0)
+		p.write("]")
+	case *ast.HashPattern:
+		p.write("{")
+		for i, entry := range pattern.Entries {
+			if i > 0 {
+				p.write(", ")
+			}
+			p.hashPatternEntry(entry)
+		}
+		p.rest(pattern.Rest, len(pattern.Entries) > 0)
+		p.write("}")
+	default:
+		panic(fmt.Sprintf("printer: unexpected pattern type %T", pattern))
+	}
+}
+
+func (p *printer) rest(rest *ast.Identifier, separate bool) {
+	if rest == nil {
+		return
+	}
+
+	if separate {
+		p.write(", ")
+	}
+	p.write("..." + rest.Value)
+}
+
+func (p *printer) hashPatternEntry(entry *ast.HashPatternEntry) {
+	target := entry.Value
+	if dp, ok := target.(*ast.DefaultPattern); ok {
+		target = dp.Target
+	}
+
+	// Shorthand {name} or {name = 1}
+	if ident, ok := target.(*ast.Identifier); !ok || ident.Value != entry.Key.Value {
+		p.write(entry.Key.Value + ": ")
+	}
+
+	p.pattern(entry.Value)
+}
+
+// Prints exp, between parentheses when it binds looser than precedence
+func (p *printer) expression(exp ast.Expression, precedence int) {
+	own := precedenceOf(exp)
+	if own < precedence {
+		p.write("(")
+		defer p.write(")")
+	}
+
+	switch exp := exp.(type) {
+	case *ast.Identifier:
+		p.write(exp.Value)
+	case *ast.IntegerLiteral, *ast.BigIntegerLiteral, *ast.FloatLiteral, *ast.Boolean:
+		p.write(exp.TokenLiteral())
+	case *ast.PrefixExpression:
+		p.write(exp.Operator)
+		// -(-x) rather than --x
+		if exp.Operator == "-" && isNegative(exp.Right) {
+			p.expression(exp.Right, math.MaxInt)
+		} else {
+			p.expression(exp.Right, parser.PREFIX)
+		}
+	case *ast.InfixExpression:
+		// Left associative, the right operand needs parentheses on a tie
+		p.expression(exp.Left, own)
+		p.write(" " + exp.Operator + " ")
+		p.expression(exp.Right, own+1)
+	case *ast.RangeExpression:
+		p.expression(exp.Start, own)
+		p.write(exp.Token.Literal)
+		p.expression(exp.End, own+1)
+	case *ast.AssignExpression:
+		// Right associative, the target needs parentheses on a tie
+		p.expression(exp.Target, own+1)
+		p.write(" " + exp.Operator + " ")
+		p.expression(exp.Value, own)
+	case *ast.IndexExpression:
+		p.expression(exp.Left, own)
+		p.write("[")
+		p.expression(exp.Index, parser.LOWEST)
+		p.write("]")
+	default:
+		panic(fmt.Sprintf("printer: unexpected expression type %T", exp))
+	}
+}
+
+// Binding strength of exp, the same as the parser's
+func precedenceOf(exp ast.Expression) int {
+	switch exp := exp.(type) {
+	case *ast.AssignExpression:
+		return parser.ASSIGN
+	case *ast.InfixExpression:
+		// Operators are spelled like their token type
+		return parser.Precedence(token.TokenType(exp.Operator))
+	case *ast.RangeExpression:
+		return parser.RANGE
+	case *ast.PrefixExpression:
+		return parser.PREFIX
+	case *ast.IndexExpression:
+		return parser.INDEX
+	case *ast.IntegerLiteral, *ast.BigIntegerLiteral, *ast.FloatLiteral:
+		// Folded negative literals read like a prefix expression
+		if isNegative(exp) {
+			return parser.PREFIX
+		}
+	}
+
+	return math.MaxInt
+}
+
+// -x or a negative literal made by the optimizer
+func isNegative(exp ast.Expression) bool {
+	switch exp := exp.(type) {
+	case *ast.PrefixExpression:
+		return exp.Operator == "-"
+	case *ast.IntegerLiteral, *ast.BigIntegerLiteral, *ast.FloatLiteral:
+		return strings.HasPrefix(exp.TokenLiteral(), "-")
+	}
+
+	return false
+}
+
+// Nodes keep their first token in a Token field
+func tokenOf(node ast.Node) token.Token {
+	field := reflect.Indirect(reflect.ValueOf(node)).FieldByName("Token")
+	if !field.IsValid() {
+		return token.Token{}
+	}
+
+	tok, _ := field.Interface().(token.Token)
+	return tok
+}
+
+// Last source line covered by node
+func lastLine(node ast.Node) int {
+	line := 0
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		if n == nil {
+			return false
+		}
+
+		line = max(line, tokenOf(n).Line)
+
+		if block, ok := n.(*ast.BlockStatement); ok {
+			line = max(line, block.Rbrace.Line)
+		}
+		return true
+	})
+
+	return line
+}
@@ -0,0 +1,212 @@
+package printer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jeremi-traverse/monkey/ast"
+	"github.com/jeremi-traverse/monkey/token"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let x=1+2*3", "let x = 1 + 2 * 3;\n"},
+		{"(a+b)*c;a*(b+c);((a))", "(a + b) * c;\na * (b + c);\na;\n"},
+		{"a-(b-c);(a-b)-c", "a - (b - c);\na - b - c;\n"},
+		{"a=b=c;(a=b)+c", "a = b = c;\n(a = b) + c;\n"},
+		{"-(-x);!-x;-(a+b)", "-(-x);\n!-x;\n-(a + b);\n"},
+		{"h[a+1]+=2", "h[a + 1] += 2;\n"},
+		{"0..10;0..=n+1", "0..10;\n0..=n + 1;\n"},
+		{"const {a,b:[c=1,...d],...e}=x", "const {a, b: [c = 1, ...d], ...e} = x;\n"},
+		{"let {a=(b=c)}=x", "let {a = (b = c)} = x;\n"},
+		{"return;return 0x1F", "return;\nreturn 0x1F;\n"},
+		{"while(x){}", "while (x) {}\n"},
+		{"while(x){a;while(y){break}continue}",
+			"while (x) {\n\ta;\n\twhile (y) {\n\t\tbreak;\n\t}\n\tcontinue;\n}\n"},
+		{"for(;;){}for(let i=0;i<n;i+=1){}", "for (;;) {}\nfor (let i = 0; i < n; i += 1) {}\n"},
+		{"for(k,v in h){}for(v in 0..n){}", "for (k, v in h) {}\nfor (v in 0..n) {}\n"},
+		{"a\n\n\n\nb\nc", "a;\n\nb;\nc;\n"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		out, err := Format([]byte(tt.input))
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %s", tt.input, err)
+		}
+
+		if string(out) != tt.expected {
+			t.Errorf("%q: wrong output.\nexpected=%q\ngot=     %q", tt.input, tt.expected, out)
+		}
+	}
+}
+
+func TestFormatComments(t *testing.T) {
+	input := `// header
+
+
+let x = 1; // one
+// before y
+let y = 2;
+while (x) {
+  // inside
+  x -= 1;  // decrement
+
+  // end of block
+}
+while (y) { // open
+}
+// footer
+`
+
+	expected := `// header
+
+let x = 1; // one
+// before y
+let y = 2;
+while (x) {
+	// inside
+	x -= 1; // decrement
+
+	// end of block
+}
+while (y) {
+	// open
+}
+// footer
+`
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input, expected},
+		// Comments inside a statement end up after it
+		{"let x = 1 + // mid\n 2;", "let x = 1 + 2; // mid\n"},
+		{"let y = 1 + // one\n  2 + // two\n  3; // three\nz;",
+			"let y = 1 + 2 + 3; // one\n// two\n// three\nz;\n"},
+		{"for (let i = 0; // a\n i < 3; i += 1) {\n x; // b\n}",
+			"for (let i = 0; i < 3; i += 1) {\n\tx; // b\n} // a\n"},
+		{"while (x // cond\n) { // open\n}", "while (x) {\n\t// open\n} // cond\n"},
+		{"while (a) {\n\twhile (b // inner\n) {}\n}", "while (a) {\n\twhile (b) {} // inner\n}\n"},
+		// A comment after the start of the next statement is the next one's
+		{"x; y; // both", "x;\ny; // both\n"},
+		{"let x = 1; let y = // c\n 2;", "let x = 1;\nlet y = 2; // c\n"},
+		{"while (a) { x; } // after", "while (a) {\n\tx;\n} // after\n"},
+		{"while (a) { x; y; // y\n}", "while (a) {\n\tx;\n\ty; // y\n}\n"},
+	}
+
+	for _, tt := range tests {
+		out, err := Format([]byte(tt.input))
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %s", tt.input, err)
+		}
+
+		if string(out) != tt.expected {
+			t.Errorf("%q: wrong output.\nexpected=%q\ngot=     %q", tt.input, tt.expected, out)
+		}
+
+		again, _ := Format(out)
+		if !bytes.Equal(out, again) {
+			t.Errorf("%q: Format not idempotent.\nonce= %q\ntwice=%q", tt.input, out, again)
+		}
+	}
+}
+
+func TestFormatIdempotent(t *testing.T) {
+	inputs := []string{
+		"let x=1+2*3; // c\n\n\n-(-x)",
+		"while(a){// c\nfor(k,v in 0..=10){h[k]=v}}\n// end",
+		"let [a,{b=1},...c]=d; a=b-=c*(d+e)",
+	}
+
+	for _, input := range inputs {
+		once, err := Format([]byte(input))
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %s", input, err)
+		}
+
+		twice, err := Format(once)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %s", once, err)
+		}
+
+		if !bytes.Equal(once, twice) {
+			t.Errorf("Format not idempotent.\nonce= %q\ntwice=%q", once, twice)
+		}
+	}
+}
+
+func TestFprintParenthesizes(t *testing.T) {
+	tests := []struct {
+		node     ast.Expression
+		expected string
+	}{
+		{infix(infix(ident("a"), "+", ident("b")), "*", ident("c")), "(a + b) * c"},
+		{infix(ident("a"), "-", infix(ident("b"), "-", ident("c"))), "a - (b - c)"},
+		{infix(infix(ident("a"), "-", ident("b")), "-", ident("c")), "a - b - c"},
+		{prefix("-", prefix("-", ident("x"))), "-(-x)"},
+		{prefix("-", integer("-5")), "-(-5)"},
+		{infix(integer("-5"), "*", ident("x")), "-5 * x"},
+		{prefix("!", infix(ident("a"), "==", ident("b"))), "!(a == b)"},
+		{&ast.IndexExpression{Left: prefix("-", ident("h")), Index: ident("k")}, "(-h)[k]"},
+		{&ast.AssignExpression{Target: ident("a"), Operator: "=",
+			Value: &ast.AssignExpression{Target: ident("b"), Operator: "=", Value: ident("c")}}, "a = b = c"},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+		if err := Fprint(&out, tt.node); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if out.String() != tt.expected {
+			t.Errorf("wrong output. expected=%q, got=%q", tt.expected, out.String())
+		}
+
+		// The printed source parses back to the same tree
+		formatted, err := Format(out.Bytes())
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %s", out.String(), err)
+		}
+		if string(formatted) != tt.expected+";\n" {
+			t.Errorf("%q doesn't round trip. got=%q", tt.expected, formatted)
+		}
+	}
+}
+
+func TestFormatError(t *testing.T) {
+	inputs := []string{
+		"let = 5;",
+		// Not f; (x); which would change the program
+		"f(x);",
+		// Not for (return 1;;;) {} which doesn't parse
+		"for (return 1; ;) {}",
+	}
+
+	for _, input := range inputs {
+		out, err := Format([]byte(input))
+		if err == nil {
+			t.Errorf("%q: expected a parse error, got output %q", input, out)
+		}
+	}
+}
+
+func ident(name string) *ast.Identifier {
+	return &ast.Identifier{Token: token.Token{Type: token.IDENT, Literal: name}, Value: name}
+}
+
+func integer(literal string) *ast.IntegerLiteral {
+	return &ast.IntegerLiteral{Token: token.Token{Type: token.INT, Literal: literal}}
+}
+
+func prefix(operator string, right ast.Expression) *ast.PrefixExpression {
+	return &ast.PrefixExpression{Operator: operator, Right: right}
+}
+
+func infix(left ast.Expression, operator string, right ast.Expression) *ast.InfixExpression {
+	return &ast.InfixExpression{Left: left, Operator: operator, Right: right}
+}
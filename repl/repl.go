@@ -24,13 +24,6 @@ func Start(in io.Reader, out io.Writer) {
 
 		line := scanner.Text()
 		lexer := lexer.New(line)
-		/*
-			i := 0
-				for tok := lexer.NextToken(); i < 6; tok = lexer.NextToken() {
-					fmt.Fprintf(out, "%+v\n", tok)
-					i++
-				}
-		*/
 		p := parser.New(lexer)
 		p.ParseProgram()
 
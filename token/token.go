@@ -3,18 +3,29 @@ package token
 type TokenType string
 
 type Token struct {
-	Type    TokenType
-	Literal string // Lexer can be optimized by using an int/byte
+	Type    TokenType `json:"type"`
+	Literal string    `json:"literal"` // Lexer can be optimized by using an int/byte
+	Line    int       `json:"line"`    // line of the first char, starting at 1
+	Column  int       `json:"column"`  // column of the first char, starting at 1
+
+	// Whitespace and comments before the token, only kept by lexer.NewWithTrivia
+	Leading string `json:"leading,omitempty"`
 }
 
 var identKeywords = map[string]TokenType{
-	"fn":     FUNCTION,
-	"let":    LET,
-	"true":   TRUE,
-	"false":  FALSE,
-	"if":     IF,
-	"else":   ELSE,
-	"return": RETURN,
+	"fn":       FUNCTION,
+	"let":      LET,
+	"const":    CONST,
+	"true":     TRUE,
+	"false":    FALSE,
+	"if":       IF,
+	"else":     ELSE,
+	"return":   RETURN,
+	"while":    WHILE,
+	"for":      FOR,
+	"break":    BREAK,
+	"continue": CONTINUE,
+	"in":       IN,
 }
 
 // Check if identifier is a default keyword
@@ -29,10 +40,12 @@ func LookupIdent(ident string) TokenType {
 const (
 	ILLEGAL = "ILLEGAL"
 	EOF     = "EOF"
+	COMMENT = "COMMENT" // Only reported by Lexer.Comments
 
 	// Identifiers + literals
 	IDENT = "IDENT" // add, foobar, x, y
 	INT   = "INT"   // 1234567
+	FLOAT = "FLOAT" // 3.14, 1e-9
 
 	// Operators
 	ASSIGN   = "="
@@ -44,24 +57,43 @@ const (
 	EQ       = "=="
 	NOT_EQ   = "!="
 
+	PLUS_ASSIGN     = "+="
+	MINUS_ASSIGN    = "-="
+	ASTERISK_ASSIGN = "*="
+	SLASH_ASSIGN    = "/="
+
 	LT = "<"
 	GT = ">"
 
+	RANGE    = ".."  // 0..10, 10 excluded
+	RANGE_EQ = "..=" // 0..=10, 10 included
+	ELLIPSIS = "..." // let [a, ...rest] = arr
+
 	// Delimiters
 	COMMA     = ","
 	SEMICOLON = ";"
+	COLON     = ":"
 
 	LPAREN = "("
 	RPAREN = ")"
 	LBRACE = "{"
 	RBRACE = "}"
 
+	LBRACKET = "["
+	RBRACKET = "]"
+
 	// Keywords
 	FUNCTION = "FUNCTION"
 	LET      = "LET"
+	CONST    = "CONST"
 	RETURN   = "RETURN"
 	TRUE     = "TRUE"
 	FALSE    = "FALSE"
 	IF       = "IF"
 	ELSE     = "ELSE"
+	WHILE    = "WHILE"
+	FOR      = "FOR"
+	BREAK    = "BREAK"
+	CONTINUE = "CONTINUE"
+	IN       = "IN"
 )
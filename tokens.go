@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/jeremi-traverse/monkey/lexer"
+)
+
+// monkey tokens [file]
+// Prints the tokens of the file, or of the standard input, one per line:
+//
+//	1:5	IDENT	"x"
+func runTokens(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("tokens", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: monkey tokens [file]")
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if flags.NArg() > 1 {
+		flags.Usage()
+		return 2
+	}
+
+	filename, src, err := readSource(flags.Arg(0), stdin)
+	if err != nil {
+		fmt.Fprintf(stderr, "monkey tokens: %s\n", err)
+		return 2
+	}
+
+	tokens, errs := lexer.Tokenize(string(src))
+	for _, tok := range tokens {
+		fmt.Fprintf(stdout, "%d:%d\t%s\t%q\n", tok.Line, tok.Column, tok.Type, tok.Literal)
+	}
+
+	for _, err := range errs {
+		fmt.Fprintf(stderr, "%s: %s\n", filename, err)
+	}
+	if len(errs) != 0 {
+		return 1
+	}
+
+	return 0
+}